@@ -1,6 +1,10 @@
 package main
 
 import (
+	"context"
+	"os/signal"
+	"syscall"
+
 	"github.com/bbralion/CTFloodBot/pkg/handlers"
 	"github.com/bbralion/CTFloodBot/pkg/utils"
 	telegramapi "github.com/go-telegram-bot-api/telegram-bot-api"
@@ -25,5 +29,11 @@ func main() {
 		Logger: logger,
 		Config: config,
 	}
-	handler.Run()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	if err := handler.Start(ctx); err != nil {
+		logger.Fatal("Failed to start handler", zap.Error(err))
+	}
+	<-handler.Wait()
 }
@@ -0,0 +1,69 @@
+// Command issue-token mints a short-lived JWT for a single client, for operators
+// who would otherwise have to hand out a permanently-valid static token:
+//
+//	issue-token -name handler-aboba -hs256-key "$MULTIPLEXER_AUTH_KEY" -ttl 24h
+//	issue-token -name handler-aboba -hs256-key "$KEY" -right "POST:/internal/register" -right "GET:/proxy/*"
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bbralion/CTFloodBot/pkg/services"
+)
+
+// rightsFlag accumulates repeated -right METHOD:PATH flags into a services.Client.Rights map.
+type rightsFlag map[string][]string
+
+func (r rightsFlag) String() string {
+	return fmt.Sprint(map[string][]string(r))
+}
+
+func (r rightsFlag) Set(value string) error {
+	method, pattern, ok := strings.Cut(value, ":")
+	if !ok || method == "" || pattern == "" {
+		return fmt.Errorf("expected METHOD:PATH, got %q", value)
+	}
+	r[method] = append(r[method], pattern)
+	return nil
+}
+
+func main() {
+	name := flag.String("name", "", "client name to embed in the token's name claim")
+	hmacKey := flag.String("hs256-key", "", "shared secret used to sign the token with HS256")
+	issuer := flag.String("iss", "", "value of the token's iss claim, if any")
+	audience := flag.String("aud", "", "value of the token's aud claim, if any")
+	ttl := flag.Duration("ttl", time.Hour, "how long the issued token remains valid")
+	rights := make(rightsFlag)
+	flag.Var(rights, "right", "a METHOD:PATH the token is allowed to access, e.g. POST:/internal/register; repeatable, unrestricted if omitted")
+	flag.Parse()
+
+	if *name == "" {
+		log.Fatal("-name is required")
+	}
+	if *hmacKey == "" {
+		log.Fatal("-hs256-key is required")
+	}
+
+	opts := []services.JWTOption{services.WithHS256Key([]byte(*hmacKey))}
+	if *issuer != "" {
+		opts = append(opts, services.WithIssuer(*issuer))
+	}
+	if *audience != "" {
+		opts = append(opts, services.WithAudience(*audience))
+	}
+
+	issuerSvc, err := services.NewJWTIssuer(opts...)
+	if err != nil {
+		log.Fatalf("failed to set up token issuer: %v", err)
+	}
+
+	token, err := issuerSvc.Issue(services.Client{Name: *name, Rights: rights}, *ttl)
+	if err != nil {
+		log.Fatalf("failed to issue token: %v", err)
+	}
+	fmt.Println(token)
+}
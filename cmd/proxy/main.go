@@ -0,0 +1,92 @@
+// Command proxy is the multiplexer proxy server: it long-polls Telegram for
+// updates and fans each one out to every registered handler, over both the HTTP
+// registration API (pkg/services.RegistrationServer) and the gRPC one
+// (internal/proxy.GRPC), so a handler can register through whichever it prefers.
+//
+//	proxy -telegram-token "$TELEGRAM_TOKEN" -hs256-key "$MULTIPLEXER_AUTH_KEY" \
+//		-advertised-endpoint https://proxy.example.com
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os/signal"
+	"syscall"
+
+	"github.com/bbralion/CTFloodBot/internal/proxy"
+	"github.com/bbralion/CTFloodBot/pkg/services"
+	"github.com/go-logr/logr"
+	telegramapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+func main() {
+	token := flag.String("telegram-token", "", "Telegram Bot API token")
+	httpAddr := flag.String("http-addr", ":8080", "address the HTTP registration API listens on")
+	grpcAddr := flag.String("grpc-addr", ":9090", "address the gRPC registration API listens on")
+	advertisedEndpoint := flag.String("advertised-endpoint", "", "this proxy's externally reachable HTTP endpoint, returned by GetConfig")
+	hmacKey := flag.String("hs256-key", "", "shared secret used to verify client JWTs")
+	buffer := flag.Int("buffer", services.DefaultUpdatesBuffer, "per-handler update channel buffer size")
+	flag.Parse()
+
+	if *token == "" {
+		log.Fatal("-telegram-token is required")
+	}
+	if *advertisedEndpoint == "" {
+		log.Fatal("-advertised-endpoint is required")
+	}
+	if *hmacKey == "" {
+		log.Fatal("-hs256-key is required")
+	}
+
+	logger := logr.Discard()
+
+	api, err := telegramapi.NewBotAPI(*token)
+	if err != nil {
+		log.Fatalf("failed to create telegram api: %v", err)
+	}
+
+	authn, err := services.NewJWTAuthenticator(services.WithHS256Key([]byte(*hmacKey)))
+	if err != nil {
+		log.Fatalf("failed to set up authenticator: %v", err)
+	}
+
+	mux := services.NewMultiplexer(*buffer)
+	regServer := services.NewRegistrationServer(logger, authn, mux)
+
+	httpMux := http.NewServeMux()
+	httpMux.HandleFunc("/register", regServer.ServeRegister)
+	httpMux.HandleFunc("/register/renew", regServer.ServeRenew)
+	httpServer := &http.Server{Addr: *httpAddr, Handler: httpMux}
+
+	grpcProxy := &proxy.GRPC{
+		AdvertisedHTTPEndpoint: *advertisedEndpoint,
+		Addr:                   *grpcAddr,
+		Logger:                 logger,
+		AuthProvider:           authn,
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	go func() {
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("HTTP registration API failed: %v", err)
+		}
+	}()
+	go func() {
+		if err := grpcProxy.ListenAndServe(); err != nil {
+			log.Fatalf("gRPC registration API failed: %v", err)
+		}
+	}()
+
+	provider := services.NewPollingUpdateProvider(logger, api)
+	for update := range provider.Updates(ctx) {
+		mux.Serve(update)
+		grpcProxy.Serve(update)
+	}
+
+	_ = httpServer.Close()
+	_ = grpcProxy.Shutdown(context.Background())
+}
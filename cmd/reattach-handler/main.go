@@ -0,0 +1,41 @@
+// Command reattach-handler is an example of a handler that runs outside of the
+// multiplexer's supervision, attaching to an already-running multiplexer described
+// by the REATTACH_MULTIPLEXER environment variable. This is useful for attaching a
+// debugger (delve, VS Code) to a single handler without restarting the whole stack:
+//
+//	REATTACH_MULTIPLEXER='{"address":"localhost:9090","matchers":["^/aboba$"]}' \
+//		dlv debug ./cmd/reattach-handler
+package main
+
+import (
+	"context"
+	"log"
+	"os/signal"
+	"syscall"
+
+	"github.com/bbralion/CTFloodBot/pkg/services"
+	"github.com/go-logr/logr"
+)
+
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	registrar, matchers, err := services.NewReattachRegistrar(logr.Discard())
+	if err != nil {
+		log.Fatalf("failed to attach to multiplexer: %v", err)
+	}
+
+	conn, err := registrar.Register(ctx, matchers)
+	if err != nil {
+		log.Fatalf("failed to register handler: %v", err)
+	}
+
+	for update := range conn.Updates {
+		if update.Error != nil {
+			log.Printf("registration error: %v", update.Error)
+			continue
+		}
+		log.Printf("received update: %+v", update.Update)
+	}
+}
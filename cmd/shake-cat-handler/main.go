@@ -1,7 +1,10 @@
 package main
 
 import (
+	"context"
+	"os/signal"
 	"strings"
+	"syscall"
 
 	"github.com/bbralion/CTFloodBot/pkg/core"
 	"github.com/bbralion/CTFloodBot/pkg/handlers"
@@ -40,5 +43,11 @@ func main() {
 		Logger: logger,
 		Config: config,
 	}
-	handler.Run()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	if err := handler.Start(ctx); err != nil {
+		logger.Fatal("Failed to start handler", zap.Error(err))
+	}
+	<-handler.Wait()
 }
@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 type TelegramAPI struct {
 	Token    string
 	Endpoint string
@@ -8,16 +10,49 @@ type TelegramAPI struct {
 type HTTPProxy struct {
 	AdvertisedEndpoint string `mapstructure:"advertised_endpoint"`
 	Listen             string
-	Allow              []string
+}
+
+// GRPCProxyTLS configures mTLS for the gRPC proxy, letting it run over the
+// public internet without a separate TLS-terminating reverse proxy. See
+// auth.TLSConfig, which mirrors these fields.
+type GRPCProxyTLS struct {
+	CertFile           string `mapstructure:"cert_file"`
+	KeyFile            string `mapstructure:"key_file"`
+	ClientCAFile       string `mapstructure:"client_ca_file"`
+	ServerName         string `mapstructure:"server_name"`
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"`
+}
+
+// GRPCProxyKeepalive configures gRPC keepalive pings for the proxy, so idle NAT
+// boxes and load balancers don't silently kill the long-lived streaming RPC used
+// to ship updates. See auth.KeepaliveConfig, which mirrors these fields.
+type GRPCProxyKeepalive struct {
+	Time                time.Duration `mapstructure:"time"`
+	Timeout             time.Duration `mapstructure:"timeout"`
+	PermitWithoutStream bool          `mapstructure:"permit_without_stream"`
+	MinTime             time.Duration `mapstructure:"min_time"`
+	MaxConnectionIdle   time.Duration `mapstructure:"max_connection_idle"`
+	MaxConnectionAge    time.Duration `mapstructure:"max_connection_age"`
 }
 
 type GRPCProxy struct {
-	Listen string
+	Listen    string
+	TLS       *GRPCProxyTLS       `mapstructure:"tls"`
+	Keepalive *GRPCProxyKeepalive `mapstructure:"keepalive"`
 }
 
 type Client struct {
 	Name  string
 	Token string
+	// Scopes are free-form capability tags for this client; see services.Client.Scopes.
+	Scopes []string `mapstructure:"scopes"`
+	// AllowMethods are glob patterns (as matched by path.Match) restricting which
+	// gRPC full methods and Telegram update commands this client may use; empty
+	// leaves the client unrestricted. Replaces the HTTPProxy-wide Allow list with
+	// per-client scoping.
+	AllowMethods []string `mapstructure:"allow_methods"`
+	// RateLimit caps this client's requests per minute; see services.Client.RateLimit.
+	RateLimit int `mapstructure:"rate_limit"`
 }
 
 type Config struct {
@@ -1,7 +1,7 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.28.0
-// 	protoc        v3.21.3
+// 	protoc-gen-go v1.31.0
+// 	protoc        (unknown)
 // source: mux.proto
 
 package genproto
@@ -20,32 +20,793 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type MatchField int32
+
+const (
+	MatchField_MATCH_FIELD_TEXT    MatchField = 0
+	MatchField_MATCH_FIELD_CAPTION MatchField = 1
+)
+
+// Enum value maps for MatchField.
+var (
+	MatchField_name = map[int32]string{
+		0: "MATCH_FIELD_TEXT",
+		1: "MATCH_FIELD_CAPTION",
+	}
+	MatchField_value = map[string]int32{
+		"MATCH_FIELD_TEXT":    0,
+		"MATCH_FIELD_CAPTION": 1,
+	}
+)
+
+func (x MatchField) Enum() *MatchField {
+	p := new(MatchField)
+	*p = x
+	return p
+}
+
+func (x MatchField) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MatchField) Descriptor() protoreflect.EnumDescriptor {
+	return file_mux_proto_enumTypes[0].Descriptor()
+}
+
+func (MatchField) Type() protoreflect.EnumType {
+	return &file_mux_proto_enumTypes[0]
+}
+
+func (x MatchField) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MatchField.Descriptor instead.
+func (MatchField) EnumDescriptor() ([]byte, []int) {
+	return file_mux_proto_rawDescGZIP(), []int{0}
+}
+
 // Config specifies the information clients require to connect to the proxy.
 type Config struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ProxyEndpoint string `protobuf:"bytes,1,opt,name=proxy_endpoint,json=proxyEndpoint,proto3" json:"proxy_endpoint,omitempty"`
+	ProxyEndpoint string `protobuf:"bytes,1,opt,name=proxy_endpoint,json=proxyEndpoint,proto3" json:"proxy_endpoint,omitempty"`
+}
+
+func (x *Config) Reset() {
+	*x = Config{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mux_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Config) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Config) ProtoMessage() {}
+
+func (x *Config) ProtoReflect() protoreflect.Message {
+	mi := &file_mux_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Config.ProtoReflect.Descriptor instead.
+func (*Config) Descriptor() ([]byte, []int) {
+	return file_mux_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Config) GetProxyEndpoint() string {
+	if x != nil {
+		return x.ProxyEndpoint
+	}
+	return ""
+}
+
+// Update is a single update received by the proxy, passed as the actual stringified update object.
+type Update struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Json []byte `protobuf:"bytes,1,opt,name=json,proto3" json:"json,omitempty"`
+}
+
+func (x *Update) Reset() {
+	*x = Update{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mux_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Update) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Update) ProtoMessage() {}
+
+func (x *Update) ProtoReflect() protoreflect.Message {
+	mi := &file_mux_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Update.ProtoReflect.Descriptor instead.
+func (*Update) Descriptor() ([]byte, []int) {
+	return file_mux_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Update) GetJson() []byte {
+	if x != nil {
+		return x.Json
+	}
+	return nil
+}
+
+// Action is an outbound Telegram Bot API call a handler wants the proxy to make on
+// its behalf (sendMessage, answerCallbackQuery, editMessageText, ...), so the handler
+// never needs its own bot token. correlation_id is echoed back on the matching
+// ActionResult.
+type Action struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CorrelationId string `protobuf:"bytes,1,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+	Method        string `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`
+	Body          []byte `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+}
+
+func (x *Action) Reset() {
+	*x = Action{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mux_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Action) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Action) ProtoMessage() {}
+
+func (x *Action) ProtoReflect() protoreflect.Message {
+	mi := &file_mux_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Action.ProtoReflect.Descriptor instead.
+func (*Action) Descriptor() ([]byte, []int) {
+	return file_mux_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Action) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+func (x *Action) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *Action) GetBody() []byte {
+	if x != nil {
+		return x.Body
+	}
+	return nil
+}
+
+// ActionResult is the proxy's response to an Action, keyed by correlation_id so a
+// handler juggling several in-flight Actions can match results back up.
+type ActionResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CorrelationId string `protobuf:"bytes,1,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+	Ok            bool   `protobuf:"varint,2,opt,name=ok,proto3" json:"ok,omitempty"`
+	Body          []byte `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	Error         string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *ActionResult) Reset() {
+	*x = ActionResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mux_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ActionResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActionResult) ProtoMessage() {}
+
+func (x *ActionResult) ProtoReflect() protoreflect.Message {
+	mi := &file_mux_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActionResult.ProtoReflect.Descriptor instead.
+func (*ActionResult) Descriptor() ([]byte, []int) {
+	return file_mux_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ActionResult) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+func (x *ActionResult) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *ActionResult) GetBody() []byte {
+	if x != nil {
+		return x.Body
+	}
+	return nil
+}
+
+func (x *ActionResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// HandlerMessage is a single frame on the bidirectional RegisterHandler stream: the
+// first frame a handler sends is always a RegisterRequest, after which it may send
+// any number of Actions; the proxy replies with a stream of Updates interleaved with
+// the ActionResults for those Actions.
+type HandlerMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Payload:
+	//
+	//	*HandlerMessage_Register
+	//	*HandlerMessage_Update
+	//	*HandlerMessage_Action
+	//	*HandlerMessage_ActionResult
+	Payload isHandlerMessage_Payload `protobuf_oneof:"payload"`
+}
+
+func (x *HandlerMessage) Reset() {
+	*x = HandlerMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mux_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HandlerMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HandlerMessage) ProtoMessage() {}
+
+func (x *HandlerMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_mux_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HandlerMessage.ProtoReflect.Descriptor instead.
+func (*HandlerMessage) Descriptor() ([]byte, []int) {
+	return file_mux_proto_rawDescGZIP(), []int{4}
+}
+
+func (m *HandlerMessage) GetPayload() isHandlerMessage_Payload {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (x *HandlerMessage) GetRegister() *RegisterRequest {
+	if x, ok := x.GetPayload().(*HandlerMessage_Register); ok {
+		return x.Register
+	}
+	return nil
+}
+
+func (x *HandlerMessage) GetUpdate() *Update {
+	if x, ok := x.GetPayload().(*HandlerMessage_Update); ok {
+		return x.Update
+	}
+	return nil
+}
+
+func (x *HandlerMessage) GetAction() *Action {
+	if x, ok := x.GetPayload().(*HandlerMessage_Action); ok {
+		return x.Action
+	}
+	return nil
+}
+
+func (x *HandlerMessage) GetActionResult() *ActionResult {
+	if x, ok := x.GetPayload().(*HandlerMessage_ActionResult); ok {
+		return x.ActionResult
+	}
+	return nil
+}
+
+type isHandlerMessage_Payload interface {
+	isHandlerMessage_Payload()
+}
+
+type HandlerMessage_Register struct {
+	Register *RegisterRequest `protobuf:"bytes,1,opt,name=register,proto3,oneof"`
+}
+
+type HandlerMessage_Update struct {
+	Update *Update `protobuf:"bytes,2,opt,name=update,proto3,oneof"`
+}
+
+type HandlerMessage_Action struct {
+	Action *Action `protobuf:"bytes,3,opt,name=action,proto3,oneof"`
+}
+
+type HandlerMessage_ActionResult struct {
+	ActionResult *ActionResult `protobuf:"bytes,4,opt,name=action_result,json=actionResult,proto3,oneof"`
+}
+
+func (*HandlerMessage_Register) isHandlerMessage_Payload() {}
+
+func (*HandlerMessage_Update) isHandlerMessage_Payload() {}
+
+func (*HandlerMessage_Action) isHandlerMessage_Payload() {}
+
+func (*HandlerMessage_ActionResult) isHandlerMessage_Payload() {}
+
+type ConfigRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ConfigRequest) Reset() {
+	*x = ConfigRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mux_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigRequest) ProtoMessage() {}
+
+func (x *ConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mux_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigRequest.ProtoReflect.Descriptor instead.
+func (*ConfigRequest) Descriptor() ([]byte, []int) {
+	return file_mux_proto_rawDescGZIP(), []int{5}
+}
+
+type ConfigResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Config *Config `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+}
+
+func (x *ConfigResponse) Reset() {
+	*x = ConfigResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mux_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConfigResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigResponse) ProtoMessage() {}
+
+func (x *ConfigResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mux_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigResponse.ProtoReflect.Descriptor instead.
+func (*ConfigResponse) Descriptor() ([]byte, []int) {
+	return file_mux_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ConfigResponse) GetConfig() *Config {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+// Matcher is a single routing predicate. The proxy compiles these once at
+// RegisterHandler entry instead of re-parsing them on every update.
+type Matcher struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Kind:
+	//
+	//	*Matcher_Command
+	//	*Matcher_Regex
+	//	*Matcher_CallbackData
+	//	*Matcher_MessageType
+	//	*Matcher_ChatType
+	//	*Matcher_All
+	//	*Matcher_Any
+	//	*Matcher_Not
+	Kind isMatcher_Kind `protobuf_oneof:"kind"`
+}
+
+func (x *Matcher) Reset() {
+	*x = Matcher{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mux_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Matcher) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Matcher) ProtoMessage() {}
+
+func (x *Matcher) ProtoReflect() protoreflect.Message {
+	mi := &file_mux_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Matcher.ProtoReflect.Descriptor instead.
+func (*Matcher) Descriptor() ([]byte, []int) {
+	return file_mux_proto_rawDescGZIP(), []int{7}
+}
+
+func (m *Matcher) GetKind() isMatcher_Kind {
+	if m != nil {
+		return m.Kind
+	}
+	return nil
+}
+
+func (x *Matcher) GetCommand() *CommandMatch {
+	if x, ok := x.GetKind().(*Matcher_Command); ok {
+		return x.Command
+	}
+	return nil
+}
+
+func (x *Matcher) GetRegex() *RegexMatch {
+	if x, ok := x.GetKind().(*Matcher_Regex); ok {
+		return x.Regex
+	}
+	return nil
+}
+
+func (x *Matcher) GetCallbackData() *CallbackDataMatch {
+	if x, ok := x.GetKind().(*Matcher_CallbackData); ok {
+		return x.CallbackData
+	}
+	return nil
+}
+
+func (x *Matcher) GetMessageType() *MessageTypeMatch {
+	if x, ok := x.GetKind().(*Matcher_MessageType); ok {
+		return x.MessageType
+	}
+	return nil
+}
+
+func (x *Matcher) GetChatType() *ChatTypeMatch {
+	if x, ok := x.GetKind().(*Matcher_ChatType); ok {
+		return x.ChatType
+	}
+	return nil
+}
+
+func (x *Matcher) GetAll() *All {
+	if x, ok := x.GetKind().(*Matcher_All); ok {
+		return x.All
+	}
+	return nil
+}
+
+func (x *Matcher) GetAny() *Any {
+	if x, ok := x.GetKind().(*Matcher_Any); ok {
+		return x.Any
+	}
+	return nil
+}
+
+func (x *Matcher) GetNot() *Not {
+	if x, ok := x.GetKind().(*Matcher_Not); ok {
+		return x.Not
+	}
+	return nil
+}
+
+type isMatcher_Kind interface {
+	isMatcher_Kind()
+}
+
+type Matcher_Command struct {
+	Command *CommandMatch `protobuf:"bytes,1,opt,name=command,proto3,oneof"`
+}
+
+type Matcher_Regex struct {
+	Regex *RegexMatch `protobuf:"bytes,2,opt,name=regex,proto3,oneof"`
+}
+
+type Matcher_CallbackData struct {
+	CallbackData *CallbackDataMatch `protobuf:"bytes,3,opt,name=callback_data,json=callbackData,proto3,oneof"`
+}
+
+type Matcher_MessageType struct {
+	MessageType *MessageTypeMatch `protobuf:"bytes,4,opt,name=message_type,json=messageType,proto3,oneof"`
+}
+
+type Matcher_ChatType struct {
+	ChatType *ChatTypeMatch `protobuf:"bytes,5,opt,name=chat_type,json=chatType,proto3,oneof"`
+}
+
+type Matcher_All struct {
+	All *All `protobuf:"bytes,6,opt,name=all,proto3,oneof"`
+}
+
+type Matcher_Any struct {
+	Any *Any `protobuf:"bytes,7,opt,name=any,proto3,oneof"`
+}
+
+type Matcher_Not struct {
+	Not *Not `protobuf:"bytes,8,opt,name=not,proto3,oneof"`
+}
+
+func (*Matcher_Command) isMatcher_Kind() {}
+
+func (*Matcher_Regex) isMatcher_Kind() {}
+
+func (*Matcher_CallbackData) isMatcher_Kind() {}
+
+func (*Matcher_MessageType) isMatcher_Kind() {}
+
+func (*Matcher_ChatType) isMatcher_Kind() {}
+
+func (*Matcher_All) isMatcher_Kind() {}
+
+func (*Matcher_Any) isMatcher_Kind() {}
+
+func (*Matcher_Not) isMatcher_Kind() {}
+
+// CommandMatch matches a bot command, e.g. "/start".
+type CommandMatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name                string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	BotUsernameRequired bool   `protobuf:"varint,2,opt,name=bot_username_required,json=botUsernameRequired,proto3" json:"bot_username_required,omitempty"`
+}
+
+func (x *CommandMatch) Reset() {
+	*x = CommandMatch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mux_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CommandMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommandMatch) ProtoMessage() {}
+
+func (x *CommandMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_mux_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommandMatch.ProtoReflect.Descriptor instead.
+func (*CommandMatch) Descriptor() ([]byte, []int) {
+	return file_mux_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CommandMatch) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CommandMatch) GetBotUsernameRequired() bool {
+	if x != nil {
+		return x.BotUsernameRequired
+	}
+	return false
+}
+
+// RegexMatch matches a message's text or caption against a regular expression.
+// pattern is capped at a small length (see models.MaxRegexPatternLength) even
+// though RE2 is already immune to catastrophic backtracking.
+type RegexMatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pattern string     `protobuf:"bytes,1,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	Field   MatchField `protobuf:"varint,2,opt,name=field,proto3,enum=mux.MatchField" json:"field,omitempty"`
+}
+
+func (x *RegexMatch) Reset() {
+	*x = RegexMatch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mux_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RegexMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegexMatch) ProtoMessage() {}
+
+func (x *RegexMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_mux_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegexMatch.ProtoReflect.Descriptor instead.
+func (*RegexMatch) Descriptor() ([]byte, []int) {
+	return file_mux_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RegexMatch) GetPattern() string {
+	if x != nil {
+		return x.Pattern
+	}
+	return ""
+}
+
+func (x *RegexMatch) GetField() MatchField {
+	if x != nil {
+		return x.Field
+	}
+	return MatchField_MATCH_FIELD_TEXT
+}
+
+// CallbackDataMatch matches a callback query's data by prefix.
+type CallbackDataMatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Prefix string `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
 }
 
-func (x *Config) Reset() {
-	*x = Config{}
+func (x *CallbackDataMatch) Reset() {
+	*x = CallbackDataMatch{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_mux_proto_msgTypes[0]
+		mi := &file_mux_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Config) String() string {
+func (x *CallbackDataMatch) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Config) ProtoMessage() {}
+func (*CallbackDataMatch) ProtoMessage() {}
 
-func (x *Config) ProtoReflect() protoreflect.Message {
-	mi := &file_mux_proto_msgTypes[0]
+func (x *CallbackDataMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_mux_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -56,44 +817,45 @@ func (x *Config) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Config.ProtoReflect.Descriptor instead.
-func (*Config) Descriptor() ([]byte, []int) {
-	return file_mux_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use CallbackDataMatch.ProtoReflect.Descriptor instead.
+func (*CallbackDataMatch) Descriptor() ([]byte, []int) {
+	return file_mux_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *Config) GetProxyEndpoint() string {
+func (x *CallbackDataMatch) GetPrefix() string {
 	if x != nil {
-		return x.ProxyEndpoint
+		return x.Prefix
 	}
 	return ""
 }
 
-// Update is a single update received by the proxy, passed as the actual stringified update object.
-type Update struct {
+// MessageTypeMatch matches messages containing any of the given content types
+// ("text", "photo", "document", "voice", "sticker").
+type MessageTypeMatch struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Json []byte `protobuf:"bytes,1,opt,name=json,proto3" json:"json,omitempty"`
+	Types []string `protobuf:"bytes,1,rep,name=types,proto3" json:"types,omitempty"`
 }
 
-func (x *Update) Reset() {
-	*x = Update{}
+func (x *MessageTypeMatch) Reset() {
+	*x = MessageTypeMatch{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_mux_proto_msgTypes[1]
+		mi := &file_mux_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Update) String() string {
+func (x *MessageTypeMatch) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Update) ProtoMessage() {}
+func (*MessageTypeMatch) ProtoMessage() {}
 
-func (x *Update) ProtoReflect() protoreflect.Message {
-	mi := &file_mux_proto_msgTypes[1]
+func (x *MessageTypeMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_mux_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -104,41 +866,45 @@ func (x *Update) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Update.ProtoReflect.Descriptor instead.
-func (*Update) Descriptor() ([]byte, []int) {
-	return file_mux_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use MessageTypeMatch.ProtoReflect.Descriptor instead.
+func (*MessageTypeMatch) Descriptor() ([]byte, []int) {
+	return file_mux_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *Update) GetJson() []byte {
+func (x *MessageTypeMatch) GetTypes() []string {
 	if x != nil {
-		return x.Json
+		return x.Types
 	}
 	return nil
 }
 
-type ConfigRequest struct {
+// ChatTypeMatch matches updates whose chat is of one of the given types
+// ("private", "group", "supergroup", "channel").
+type ChatTypeMatch struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Types []string `protobuf:"bytes,1,rep,name=types,proto3" json:"types,omitempty"`
 }
 
-func (x *ConfigRequest) Reset() {
-	*x = ConfigRequest{}
+func (x *ChatTypeMatch) Reset() {
+	*x = ChatTypeMatch{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_mux_proto_msgTypes[2]
+		mi := &file_mux_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ConfigRequest) String() string {
+func (x *ChatTypeMatch) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ConfigRequest) ProtoMessage() {}
+func (*ChatTypeMatch) ProtoMessage() {}
 
-func (x *ConfigRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mux_proto_msgTypes[2]
+func (x *ChatTypeMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_mux_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -149,36 +915,83 @@ func (x *ConfigRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ConfigRequest.ProtoReflect.Descriptor instead.
-func (*ConfigRequest) Descriptor() ([]byte, []int) {
-	return file_mux_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use ChatTypeMatch.ProtoReflect.Descriptor instead.
+func (*ChatTypeMatch) Descriptor() ([]byte, []int) {
+	return file_mux_proto_rawDescGZIP(), []int{12}
 }
 
-type ConfigResponse struct {
+func (x *ChatTypeMatch) GetTypes() []string {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+// All matches every update.
+type All struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+}
 
-	Config *Config `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+func (x *All) Reset() {
+	*x = All{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mux_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-func (x *ConfigResponse) Reset() {
-	*x = ConfigResponse{}
+func (x *All) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*All) ProtoMessage() {}
+
+func (x *All) ProtoReflect() protoreflect.Message {
+	mi := &file_mux_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use All.ProtoReflect.Descriptor instead.
+func (*All) Descriptor() ([]byte, []int) {
+	return file_mux_proto_rawDescGZIP(), []int{13}
+}
+
+// Any matches if any of its children match (logical OR).
+type Any struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Children []*Matcher `protobuf:"bytes,1,rep,name=children,proto3" json:"children,omitempty"`
+}
+
+func (x *Any) Reset() {
+	*x = Any{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_mux_proto_msgTypes[3]
+		mi := &file_mux_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ConfigResponse) String() string {
+func (x *Any) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ConfigResponse) ProtoMessage() {}
+func (*Any) ProtoMessage() {}
 
-func (x *ConfigResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mux_proto_msgTypes[3]
+func (x *Any) ProtoReflect() protoreflect.Message {
+	mi := &file_mux_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -189,14 +1002,62 @@ func (x *ConfigResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ConfigResponse.ProtoReflect.Descriptor instead.
-func (*ConfigResponse) Descriptor() ([]byte, []int) {
-	return file_mux_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use Any.ProtoReflect.Descriptor instead.
+func (*Any) Descriptor() ([]byte, []int) {
+	return file_mux_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *ConfigResponse) GetConfig() *Config {
+func (x *Any) GetChildren() []*Matcher {
 	if x != nil {
-		return x.Config
+		return x.Children
+	}
+	return nil
+}
+
+// Not matches updates its child does not.
+type Not struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Child *Matcher `protobuf:"bytes,1,opt,name=child,proto3" json:"child,omitempty"`
+}
+
+func (x *Not) Reset() {
+	*x = Not{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mux_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Not) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Not) ProtoMessage() {}
+
+func (x *Not) ProtoReflect() protoreflect.Message {
+	mi := &file_mux_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Not.ProtoReflect.Descriptor instead.
+func (*Not) Descriptor() ([]byte, []int) {
+	return file_mux_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Not) GetChild() *Matcher {
+	if x != nil {
+		return x.Child
 	}
 	return nil
 }
@@ -206,14 +1067,20 @@ type RegisterRequest struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Name     string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Matchers []string `protobuf:"bytes,2,rep,name=matchers,proto3" json:"matchers,omitempty"`
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// matchers is deprecated in favor of structured_matchers: strings of the form
+	// "^/command$" are translated server-side into a CommandMatch, anything else into
+	// a RegexMatch against the message text.
+	//
+	// Deprecated: Marked as deprecated in mux.proto.
+	Matchers           []string   `protobuf:"bytes,2,rep,name=matchers,proto3" json:"matchers,omitempty"`
+	StructuredMatchers []*Matcher `protobuf:"bytes,3,rep,name=structured_matchers,json=structuredMatchers,proto3" json:"structured_matchers,omitempty"`
 }
 
 func (x *RegisterRequest) Reset() {
 	*x = RegisterRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_mux_proto_msgTypes[4]
+		mi := &file_mux_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -226,7 +1093,7 @@ func (x *RegisterRequest) String() string {
 func (*RegisterRequest) ProtoMessage() {}
 
 func (x *RegisterRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mux_proto_msgTypes[4]
+	mi := &file_mux_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -239,7 +1106,7 @@ func (x *RegisterRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RegisterRequest.ProtoReflect.Descriptor instead.
 func (*RegisterRequest) Descriptor() ([]byte, []int) {
-	return file_mux_proto_rawDescGZIP(), []int{4}
+	return file_mux_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *RegisterRequest) GetName() string {
@@ -249,6 +1116,7 @@ func (x *RegisterRequest) GetName() string {
 	return ""
 }
 
+// Deprecated: Marked as deprecated in mux.proto.
 func (x *RegisterRequest) GetMatchers() []string {
 	if x != nil {
 		return x.Matchers
@@ -256,6 +1124,13 @@ func (x *RegisterRequest) GetMatchers() []string {
 	return nil
 }
 
+func (x *RegisterRequest) GetStructuredMatchers() []*Matcher {
+	if x != nil {
+		return x.StructuredMatchers
+	}
+	return nil
+}
+
 var File_mux_proto protoreflect.FileDescriptor
 
 var file_mux_proto_rawDesc = []byte{
@@ -265,24 +1140,110 @@ var file_mux_proto_rawDesc = []byte{
 	0x28, 0x09, 0x52, 0x0d, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e,
 	0x74, 0x22, 0x1c, 0x0a, 0x06, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6a,
 	0x73, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x6a, 0x73, 0x6f, 0x6e, 0x22,
-	0x0f, 0x0a, 0x0d, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x22, 0x35, 0x0a, 0x0e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x23, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x6d, 0x75, 0x78, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52,
-	0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22, 0x41, 0x0a, 0x0f, 0x52, 0x65, 0x67, 0x69, 0x73,
-	0x74, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
-	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a,
-	0x0a, 0x08, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09,
-	0x52, 0x08, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x72, 0x73, 0x32, 0x82, 0x01, 0x0a, 0x12, 0x4d,
-	0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x65, 0x78, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
-	0x65, 0x12, 0x34, 0x0a, 0x09, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x12,
-	0x2e, 0x6d, 0x75, 0x78, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x13, 0x2e, 0x6d, 0x75, 0x78, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x36, 0x0a, 0x0f, 0x52, 0x65, 0x67, 0x69, 0x73,
-	0x74, 0x65, 0x72, 0x48, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x72, 0x12, 0x14, 0x2e, 0x6d, 0x75, 0x78,
-	0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x0b, 0x2e, 0x6d, 0x75, 0x78, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x30, 0x01, 0x62,
-	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x5b, 0x0a, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x72,
+	0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0d, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64,
+	0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x6f, 0x64, 0x79,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x22, 0x6f, 0x0a, 0x0c,
+	0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x25, 0x0a, 0x0e,
+	0x63, 0x6f, 0x72, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x49, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x6f, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x02, 0x6f, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0xd7, 0x01,
+	0x0a, 0x0e, 0x48, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x12, 0x32, 0x0a, 0x08, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6d, 0x75, 0x78, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65,
+	0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x08, 0x72, 0x65, 0x67, 0x69,
+	0x73, 0x74, 0x65, 0x72, 0x12, 0x25, 0x0a, 0x06, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x6d, 0x75, 0x78, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x48, 0x00, 0x52, 0x06, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x25, 0x0a, 0x06, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x6d, 0x75,
+	0x78, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x38, 0x0a, 0x0d, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x72, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x6d, 0x75, 0x78, 0x2e,
+	0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x48, 0x00, 0x52, 0x0c,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x42, 0x09, 0x0a, 0x07,
+	0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x22, 0x0f, 0x0a, 0x0d, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x35, 0x0a, 0x0e, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x23, 0x0a, 0x06, 0x63, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x6d, 0x75, 0x78,
+	0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22,
+	0xf1, 0x02, 0x0a, 0x07, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x72, 0x12, 0x2d, 0x0a, 0x07, 0x63,
+	0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x6d,
+	0x75, 0x78, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x48,
+	0x00, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x27, 0x0a, 0x05, 0x72, 0x65,
+	0x67, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6d, 0x75, 0x78, 0x2e,
+	0x52, 0x65, 0x67, 0x65, 0x78, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x48, 0x00, 0x52, 0x05, 0x72, 0x65,
+	0x67, 0x65, 0x78, 0x12, 0x3d, 0x0a, 0x0d, 0x63, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x5f,
+	0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6d, 0x75, 0x78,
+	0x2e, 0x43, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x44, 0x61, 0x74, 0x61, 0x4d, 0x61, 0x74,
+	0x63, 0x68, 0x48, 0x00, 0x52, 0x0c, 0x63, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x44, 0x61,
+	0x74, 0x61, 0x12, 0x3a, 0x0a, 0x0c, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6d, 0x75, 0x78, 0x2e, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x48,
+	0x00, 0x52, 0x0b, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x31,
+	0x0a, 0x09, 0x63, 0x68, 0x61, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x12, 0x2e, 0x6d, 0x75, 0x78, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x54, 0x79, 0x70, 0x65,
+	0x4d, 0x61, 0x74, 0x63, 0x68, 0x48, 0x00, 0x52, 0x08, 0x63, 0x68, 0x61, 0x74, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x1c, 0x0a, 0x03, 0x61, 0x6c, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x08,
+	0x2e, 0x6d, 0x75, 0x78, 0x2e, 0x41, 0x6c, 0x6c, 0x48, 0x00, 0x52, 0x03, 0x61, 0x6c, 0x6c, 0x12,
+	0x1c, 0x0a, 0x03, 0x61, 0x6e, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x08, 0x2e, 0x6d,
+	0x75, 0x78, 0x2e, 0x41, 0x6e, 0x79, 0x48, 0x00, 0x52, 0x03, 0x61, 0x6e, 0x79, 0x12, 0x1c, 0x0a,
+	0x03, 0x6e, 0x6f, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x08, 0x2e, 0x6d, 0x75, 0x78,
+	0x2e, 0x4e, 0x6f, 0x74, 0x48, 0x00, 0x52, 0x03, 0x6e, 0x6f, 0x74, 0x42, 0x06, 0x0a, 0x04, 0x6b,
+	0x69, 0x6e, 0x64, 0x22, 0x56, 0x0a, 0x0c, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4d, 0x61,
+	0x74, 0x63, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x32, 0x0a, 0x15, 0x62, 0x6f, 0x74, 0x5f, 0x75,
+	0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x13, 0x62, 0x6f, 0x74, 0x55, 0x73, 0x65, 0x72, 0x6e,
+	0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x22, 0x4d, 0x0a, 0x0a, 0x52,
+	0x65, 0x67, 0x65, 0x78, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x74,
+	0x74, 0x65, 0x72, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x61, 0x74, 0x74,
+	0x65, 0x72, 0x6e, 0x12, 0x25, 0x0a, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x0f, 0x2e, 0x6d, 0x75, 0x78, 0x2e, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x46, 0x69,
+	0x65, 0x6c, 0x64, 0x52, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x22, 0x2b, 0x0a, 0x11, 0x43, 0x61,
+	0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x44, 0x61, 0x74, 0x61, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x12,
+	0x16, 0x0a, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x22, 0x28, 0x0a, 0x10, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x74,
+	0x79, 0x70, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x74, 0x79, 0x70, 0x65,
+	0x73, 0x22, 0x25, 0x0a, 0x0d, 0x43, 0x68, 0x61, 0x74, 0x54, 0x79, 0x70, 0x65, 0x4d, 0x61, 0x74,
+	0x63, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x79, 0x70, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x05, 0x74, 0x79, 0x70, 0x65, 0x73, 0x22, 0x05, 0x0a, 0x03, 0x41, 0x6c, 0x6c, 0x22,
+	0x2f, 0x0a, 0x03, 0x41, 0x6e, 0x79, 0x12, 0x28, 0x0a, 0x08, 0x63, 0x68, 0x69, 0x6c, 0x64, 0x72,
+	0x65, 0x6e, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x6d, 0x75, 0x78, 0x2e, 0x4d,
+	0x61, 0x74, 0x63, 0x68, 0x65, 0x72, 0x52, 0x08, 0x63, 0x68, 0x69, 0x6c, 0x64, 0x72, 0x65, 0x6e,
+	0x22, 0x29, 0x0a, 0x03, 0x4e, 0x6f, 0x74, 0x12, 0x22, 0x0a, 0x05, 0x63, 0x68, 0x69, 0x6c, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x6d, 0x75, 0x78, 0x2e, 0x4d, 0x61, 0x74,
+	0x63, 0x68, 0x65, 0x72, 0x52, 0x05, 0x63, 0x68, 0x69, 0x6c, 0x64, 0x22, 0x84, 0x01, 0x0a, 0x0f,
+	0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x1e, 0x0a, 0x08, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x72, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x09, 0x42, 0x02, 0x18, 0x01, 0x52, 0x08, 0x6d, 0x61, 0x74, 0x63, 0x68,
+	0x65, 0x72, 0x73, 0x12, 0x3d, 0x0a, 0x13, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x75, 0x72, 0x65,
+	0x64, 0x5f, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x72, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0c, 0x2e, 0x6d, 0x75, 0x78, 0x2e, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x72, 0x52, 0x12,
+	0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x75, 0x72, 0x65, 0x64, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65,
+	0x72, 0x73, 0x2a, 0x3b, 0x0a, 0x0a, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x46, 0x69, 0x65, 0x6c, 0x64,
+	0x12, 0x14, 0x0a, 0x10, 0x4d, 0x41, 0x54, 0x43, 0x48, 0x5f, 0x46, 0x49, 0x45, 0x4c, 0x44, 0x5f,
+	0x54, 0x45, 0x58, 0x54, 0x10, 0x00, 0x12, 0x17, 0x0a, 0x13, 0x4d, 0x41, 0x54, 0x43, 0x48, 0x5f,
+	0x46, 0x49, 0x45, 0x4c, 0x44, 0x5f, 0x43, 0x41, 0x50, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x01, 0x32,
+	0x8b, 0x01, 0x0a, 0x12, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x65, 0x78, 0x65, 0x72, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x34, 0x0a, 0x09, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x12, 0x12, 0x2e, 0x6d, 0x75, 0x78, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x6d, 0x75, 0x78, 0x2e, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3f, 0x0a, 0x0f,
+	0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x48, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x72, 0x12,
+	0x13, 0x2e, 0x6d, 0x75, 0x78, 0x2e, 0x48, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x72, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x1a, 0x13, 0x2e, 0x6d, 0x75, 0x78, 0x2e, 0x48, 0x61, 0x6e, 0x64, 0x6c,
+	0x65, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x28, 0x01, 0x30, 0x01, 0x42, 0x32, 0x5a,
+	0x30, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x62, 0x72, 0x61,
+	0x6c, 0x69, 0x6f, 0x6e, 0x2f, 0x43, 0x54, 0x46, 0x6c, 0x6f, 0x6f, 0x64, 0x42, 0x6f, 0x74, 0x2f,
+	0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x67, 0x65, 0x6e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -297,25 +1258,55 @@ func file_mux_proto_rawDescGZIP() []byte {
 	return file_mux_proto_rawDescData
 }
 
-var file_mux_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_mux_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_mux_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
 var file_mux_proto_goTypes = []interface{}{
-	(*Config)(nil),          // 0: mux.Config
-	(*Update)(nil),          // 1: mux.Update
-	(*ConfigRequest)(nil),   // 2: mux.ConfigRequest
-	(*ConfigResponse)(nil),  // 3: mux.ConfigResponse
-	(*RegisterRequest)(nil), // 4: mux.RegisterRequest
+	(MatchField)(0),           // 0: mux.MatchField
+	(*Config)(nil),            // 1: mux.Config
+	(*Update)(nil),            // 2: mux.Update
+	(*Action)(nil),            // 3: mux.Action
+	(*ActionResult)(nil),      // 4: mux.ActionResult
+	(*HandlerMessage)(nil),    // 5: mux.HandlerMessage
+	(*ConfigRequest)(nil),     // 6: mux.ConfigRequest
+	(*ConfigResponse)(nil),    // 7: mux.ConfigResponse
+	(*Matcher)(nil),           // 8: mux.Matcher
+	(*CommandMatch)(nil),      // 9: mux.CommandMatch
+	(*RegexMatch)(nil),        // 10: mux.RegexMatch
+	(*CallbackDataMatch)(nil), // 11: mux.CallbackDataMatch
+	(*MessageTypeMatch)(nil),  // 12: mux.MessageTypeMatch
+	(*ChatTypeMatch)(nil),     // 13: mux.ChatTypeMatch
+	(*All)(nil),               // 14: mux.All
+	(*Any)(nil),               // 15: mux.Any
+	(*Not)(nil),               // 16: mux.Not
+	(*RegisterRequest)(nil),   // 17: mux.RegisterRequest
 }
 var file_mux_proto_depIdxs = []int32{
-	0, // 0: mux.ConfigResponse.config:type_name -> mux.Config
-	2, // 1: mux.MultiplexerService.GetConfig:input_type -> mux.ConfigRequest
-	4, // 2: mux.MultiplexerService.RegisterHandler:input_type -> mux.RegisterRequest
-	3, // 3: mux.MultiplexerService.GetConfig:output_type -> mux.ConfigResponse
-	1, // 4: mux.MultiplexerService.RegisterHandler:output_type -> mux.Update
-	3, // [3:5] is the sub-list for method output_type
-	1, // [1:3] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	17, // 0: mux.HandlerMessage.register:type_name -> mux.RegisterRequest
+	2,  // 1: mux.HandlerMessage.update:type_name -> mux.Update
+	3,  // 2: mux.HandlerMessage.action:type_name -> mux.Action
+	4,  // 3: mux.HandlerMessage.action_result:type_name -> mux.ActionResult
+	1,  // 4: mux.ConfigResponse.config:type_name -> mux.Config
+	9,  // 5: mux.Matcher.command:type_name -> mux.CommandMatch
+	10, // 6: mux.Matcher.regex:type_name -> mux.RegexMatch
+	11, // 7: mux.Matcher.callback_data:type_name -> mux.CallbackDataMatch
+	12, // 8: mux.Matcher.message_type:type_name -> mux.MessageTypeMatch
+	13, // 9: mux.Matcher.chat_type:type_name -> mux.ChatTypeMatch
+	14, // 10: mux.Matcher.all:type_name -> mux.All
+	15, // 11: mux.Matcher.any:type_name -> mux.Any
+	16, // 12: mux.Matcher.not:type_name -> mux.Not
+	0,  // 13: mux.RegexMatch.field:type_name -> mux.MatchField
+	8,  // 14: mux.Any.children:type_name -> mux.Matcher
+	8,  // 15: mux.Not.child:type_name -> mux.Matcher
+	8,  // 16: mux.RegisterRequest.structured_matchers:type_name -> mux.Matcher
+	6,  // 17: mux.MultiplexerService.GetConfig:input_type -> mux.ConfigRequest
+	5,  // 18: mux.MultiplexerService.RegisterHandler:input_type -> mux.HandlerMessage
+	7,  // 19: mux.MultiplexerService.GetConfig:output_type -> mux.ConfigResponse
+	5,  // 20: mux.MultiplexerService.RegisterHandler:output_type -> mux.HandlerMessage
+	19, // [19:21] is the sub-list for method output_type
+	17, // [17:19] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
 }
 
 func init() { file_mux_proto_init() }
@@ -349,7 +1340,7 @@ func file_mux_proto_init() {
 			}
 		}
 		file_mux_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ConfigRequest); i {
+			switch v := v.(*Action); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -361,7 +1352,7 @@ func file_mux_proto_init() {
 			}
 		}
 		file_mux_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ConfigResponse); i {
+			switch v := v.(*ActionResult); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -373,6 +1364,150 @@ func file_mux_proto_init() {
 			}
 		}
 		file_mux_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HandlerMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mux_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConfigRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mux_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConfigResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mux_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Matcher); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mux_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CommandMatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mux_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RegexMatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mux_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CallbackDataMatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mux_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MessageTypeMatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mux_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChatTypeMatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mux_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*All); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mux_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Any); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mux_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Not); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mux_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*RegisterRequest); i {
 			case 0:
 				return &v.state
@@ -385,18 +1520,35 @@ func file_mux_proto_init() {
 			}
 		}
 	}
+	file_mux_proto_msgTypes[4].OneofWrappers = []interface{}{
+		(*HandlerMessage_Register)(nil),
+		(*HandlerMessage_Update)(nil),
+		(*HandlerMessage_Action)(nil),
+		(*HandlerMessage_ActionResult)(nil),
+	}
+	file_mux_proto_msgTypes[7].OneofWrappers = []interface{}{
+		(*Matcher_Command)(nil),
+		(*Matcher_Regex)(nil),
+		(*Matcher_CallbackData)(nil),
+		(*Matcher_MessageType)(nil),
+		(*Matcher_ChatType)(nil),
+		(*Matcher_All)(nil),
+		(*Matcher_Any)(nil),
+		(*Matcher_Not)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_mux_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   5,
+			NumEnums:      1,
+			NumMessages:   17,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_mux_proto_goTypes,
 		DependencyIndexes: file_mux_proto_depIdxs,
+		EnumInfos:         file_mux_proto_enumTypes,
 		MessageInfos:      file_mux_proto_msgTypes,
 	}.Build()
 	File_mux_proto = out.File
@@ -0,0 +1,241 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// MatchField selects which part of an incoming Message a RegexMatcher is applied to.
+type MatchField int
+
+const (
+	FieldText MatchField = iota
+	FieldCaption
+)
+
+// Matcher is a routing predicate a handler registers with. Unlike the raw regex
+// strings a RegisterRequest used to carry, a Matcher is compiled once by the proxy
+// at RegisterHandler entry into a CompiledMatcher, instead of being re-parsed from
+// its source form on every update.
+type Matcher interface {
+	Compile() (CompiledMatcher, error)
+}
+
+// CompiledMatcher is the runtime form of a Matcher, ready to be evaluated against
+// incoming updates without further parsing or validation.
+type CompiledMatcher interface {
+	Matches(update *tgbotapi.Update) bool
+}
+
+// CommandMatcher matches a bot command, e.g. "/start". If BotUsernameRequired is
+// set, only "/start@SomeBot" (addressed explicitly) matches, not the bare form.
+type CommandMatcher struct {
+	Name                string
+	BotUsernameRequired bool
+}
+
+func (m CommandMatcher) Compile() (CompiledMatcher, error) {
+	if m.Name == "" {
+		return nil, fmt.Errorf("command matcher: name must not be empty")
+	}
+	return compiledCommandMatcher(m), nil
+}
+
+type compiledCommandMatcher CommandMatcher
+
+func (m compiledCommandMatcher) Matches(update *tgbotapi.Update) bool {
+	if update.Message == nil || !update.Message.IsCommand() || update.Message.Command() != m.Name {
+		return false
+	}
+	if !m.BotUsernameRequired {
+		return true
+	}
+	return strings.Contains(update.Message.CommandWithAt(), "@")
+}
+
+// MaxRegexPatternLength caps RegexMatcher.Pattern. RE2 (Go's regexp) is already
+// immune to catastrophic backtracking, but an unbounded pattern is still a cheap
+// way for a misbehaving handler to waste proxy CPU, so it's capped regardless.
+const MaxRegexPatternLength = 512
+
+// RegexMatcher matches a message's Text or Caption (selected by Field) against a
+// regular expression.
+type RegexMatcher struct {
+	Pattern string
+	Field   MatchField
+}
+
+func (m RegexMatcher) Compile() (CompiledMatcher, error) {
+	if len(m.Pattern) > MaxRegexPatternLength {
+		return nil, fmt.Errorf("regex matcher: pattern exceeds %d characters", MaxRegexPatternLength)
+	}
+	re, err := regexp.Compile(m.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("regex matcher: %w", err)
+	}
+	return &compiledRegexMatcher{re: re, field: m.Field}, nil
+}
+
+type compiledRegexMatcher struct {
+	re    *regexp.Regexp
+	field MatchField
+}
+
+func (m *compiledRegexMatcher) Matches(update *tgbotapi.Update) bool {
+	if update.Message == nil {
+		return false
+	}
+	if m.field == FieldCaption {
+		return m.re.MatchString(update.Message.Caption)
+	}
+	return m.re.MatchString(update.Message.Text)
+}
+
+// CallbackDataMatcher matches update.CallbackQuery.Data by prefix.
+type CallbackDataMatcher struct {
+	Prefix string
+}
+
+func (m CallbackDataMatcher) Compile() (CompiledMatcher, error) {
+	return compiledCallbackDataMatcher(m), nil
+}
+
+type compiledCallbackDataMatcher CallbackDataMatcher
+
+func (m compiledCallbackDataMatcher) Matches(update *tgbotapi.Update) bool {
+	return update.CallbackQuery != nil && strings.HasPrefix(update.CallbackQuery.Data, m.Prefix)
+}
+
+// MessageTypeMatcher matches messages containing any of the given content types
+// ("text", "photo", "document", "voice", "sticker").
+type MessageTypeMatcher struct {
+	Types []string
+}
+
+func (m MessageTypeMatcher) Compile() (CompiledMatcher, error) {
+	if len(m.Types) == 0 {
+		return nil, fmt.Errorf("message type matcher: at least one type is required")
+	}
+	return compiledMessageTypeMatcher(m), nil
+}
+
+type compiledMessageTypeMatcher MessageTypeMatcher
+
+func (m compiledMessageTypeMatcher) Matches(update *tgbotapi.Update) bool {
+	if update.Message == nil {
+		return false
+	}
+	for _, t := range m.Types {
+		switch t {
+		case "text":
+			if update.Message.Text != "" {
+				return true
+			}
+		case "photo":
+			if update.Message.Photo != nil {
+				return true
+			}
+		case "document":
+			if update.Message.Document != nil {
+				return true
+			}
+		case "voice":
+			if update.Message.Voice != nil {
+				return true
+			}
+		case "sticker":
+			if update.Message.Sticker != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ChatTypeMatcher matches updates whose chat is of one of the given types
+// ("private", "group", "supergroup", "channel").
+type ChatTypeMatcher struct {
+	Types []string
+}
+
+func (m ChatTypeMatcher) Compile() (CompiledMatcher, error) {
+	if len(m.Types) == 0 {
+		return nil, fmt.Errorf("chat type matcher: at least one type is required")
+	}
+	return compiledChatTypeMatcher(m), nil
+}
+
+type compiledChatTypeMatcher ChatTypeMatcher
+
+func (m compiledChatTypeMatcher) Matches(update *tgbotapi.Update) bool {
+	if update.Message == nil || update.Message.Chat == nil {
+		return false
+	}
+	for _, t := range m.Types {
+		if update.Message.Chat.Type == t {
+			return true
+		}
+	}
+	return false
+}
+
+// AllMatcher matches every update, e.g. for a catch-all logging handler.
+type AllMatcher struct{}
+
+func (AllMatcher) Compile() (CompiledMatcher, error) { return compiledAllMatcher{}, nil }
+
+type compiledAllMatcher struct{}
+
+func (compiledAllMatcher) Matches(*tgbotapi.Update) bool { return true }
+
+// AnyMatcher matches if any of its children match (logical OR), the same semantics
+// the old []string matchers had as a flat group.
+type AnyMatcher struct {
+	Children []Matcher
+}
+
+func (m AnyMatcher) Compile() (CompiledMatcher, error) {
+	compiled := make([]CompiledMatcher, len(m.Children))
+	for i, c := range m.Children {
+		cm, err := c.Compile()
+		if err != nil {
+			return nil, fmt.Errorf("any matcher: child %d: %w", i, err)
+		}
+		compiled[i] = cm
+	}
+	return compiledAnyMatcher(compiled), nil
+}
+
+type compiledAnyMatcher []CompiledMatcher
+
+func (m compiledAnyMatcher) Matches(update *tgbotapi.Update) bool {
+	for _, c := range m {
+		if c.Matches(update) {
+			return true
+		}
+	}
+	return false
+}
+
+// NotMatcher matches updates its child matcher does not.
+type NotMatcher struct {
+	Child Matcher
+}
+
+func (m NotMatcher) Compile() (CompiledMatcher, error) {
+	if m.Child == nil {
+		return nil, fmt.Errorf("not matcher: child must not be nil")
+	}
+	child, err := m.Child.Compile()
+	if err != nil {
+		return nil, fmt.Errorf("not matcher: %w", err)
+	}
+	return compiledNotMatcher{child}, nil
+}
+
+type compiledNotMatcher struct{ child CompiledMatcher }
+
+func (m compiledNotMatcher) Matches(update *tgbotapi.Update) bool { return !m.child.Matches(update) }
@@ -0,0 +1,143 @@
+package models
+
+import (
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/stretchr/testify/require"
+)
+
+func entities(cmdLen int) *[]tgbotapi.MessageEntity {
+	return &[]tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: cmdLen}}
+}
+
+func TestCommandMatcher(t *testing.T) {
+	req := require.New(t)
+
+	m, err := CommandMatcher{Name: "start"}.Compile()
+	req.NoError(err)
+
+	req.True(m.Matches(&tgbotapi.Update{Message: &tgbotapi.Message{Text: "/start", Entities: entities(len("/start"))}}))
+	req.False(m.Matches(&tgbotapi.Update{Message: &tgbotapi.Message{Text: "/stop", Entities: entities(len("/stop"))}}))
+	req.False(m.Matches(&tgbotapi.Update{Message: &tgbotapi.Message{Text: "hello"}}))
+	req.False(m.Matches(&tgbotapi.Update{}))
+}
+
+func TestCommandMatcher_BotUsernameRequired(t *testing.T) {
+	req := require.New(t)
+
+	m, err := CommandMatcher{Name: "start", BotUsernameRequired: true}.Compile()
+	req.NoError(err)
+
+	req.True(m.Matches(&tgbotapi.Update{Message: &tgbotapi.Message{Text: "/start@SomeBot", Entities: entities(len("/start@SomeBot"))}}))
+	req.False(m.Matches(&tgbotapi.Update{Message: &tgbotapi.Message{Text: "/start", Entities: entities(len("/start"))}}))
+}
+
+func TestRegexMatcher(t *testing.T) {
+	req := require.New(t)
+
+	m, err := RegexMatcher{Pattern: "^/aboba"}.Compile()
+	req.NoError(err)
+	req.True(m.Matches(&tgbotapi.Update{Message: &tgbotapi.Message{Text: "/aboba 1 2 3"}}))
+	req.False(m.Matches(&tgbotapi.Update{Message: &tgbotapi.Message{Text: "not it"}}))
+
+	mc, err := RegexMatcher{Pattern: "^cap$", Field: FieldCaption}.Compile()
+	req.NoError(err)
+	req.True(mc.Matches(&tgbotapi.Update{Message: &tgbotapi.Message{Caption: "cap"}}))
+	req.False(mc.Matches(&tgbotapi.Update{Message: &tgbotapi.Message{Text: "cap"}}))
+}
+
+func TestRegexMatcher_InvalidPattern(t *testing.T) {
+	req := require.New(t)
+
+	_, err := RegexMatcher{Pattern: "("}.Compile()
+	req.Error(err)
+}
+
+func TestRegexMatcher_PatternTooLong(t *testing.T) {
+	req := require.New(t)
+
+	pattern := make([]byte, MaxRegexPatternLength+1)
+	for i := range pattern {
+		pattern[i] = 'a'
+	}
+	_, err := RegexMatcher{Pattern: string(pattern)}.Compile()
+	req.Error(err)
+}
+
+func TestCallbackDataMatcher(t *testing.T) {
+	req := require.New(t)
+
+	m, err := CallbackDataMatcher{Prefix: "vote:"}.Compile()
+	req.NoError(err)
+	req.True(m.Matches(&tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{Data: "vote:yes"}}))
+	req.False(m.Matches(&tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{Data: "other"}}))
+	req.False(m.Matches(&tgbotapi.Update{}))
+}
+
+func TestMessageTypeMatcher(t *testing.T) {
+	req := require.New(t)
+
+	m, err := MessageTypeMatcher{Types: []string{"photo"}}.Compile()
+	req.NoError(err)
+	req.True(m.Matches(&tgbotapi.Update{Message: &tgbotapi.Message{Photo: &[]tgbotapi.PhotoSize{{}}}}))
+	req.False(m.Matches(&tgbotapi.Update{Message: &tgbotapi.Message{Text: "hi"}}))
+}
+
+func TestMessageTypeMatcher_RequiresAtLeastOneType(t *testing.T) {
+	req := require.New(t)
+	_, err := MessageTypeMatcher{}.Compile()
+	req.Error(err)
+}
+
+func TestChatTypeMatcher(t *testing.T) {
+	req := require.New(t)
+
+	m, err := ChatTypeMatcher{Types: []string{"group", "supergroup"}}.Compile()
+	req.NoError(err)
+	req.True(m.Matches(&tgbotapi.Update{Message: &tgbotapi.Message{Chat: &tgbotapi.Chat{Type: "group"}}}))
+	req.False(m.Matches(&tgbotapi.Update{Message: &tgbotapi.Message{Chat: &tgbotapi.Chat{Type: "private"}}}))
+}
+
+func TestAllMatcher(t *testing.T) {
+	req := require.New(t)
+
+	m, err := AllMatcher{}.Compile()
+	req.NoError(err)
+	req.True(m.Matches(&tgbotapi.Update{}))
+}
+
+func TestAnyMatcher(t *testing.T) {
+	req := require.New(t)
+
+	m, err := AnyMatcher{Children: []Matcher{
+		CommandMatcher{Name: "start"},
+		CommandMatcher{Name: "stop"},
+	}}.Compile()
+	req.NoError(err)
+
+	req.True(m.Matches(&tgbotapi.Update{Message: &tgbotapi.Message{Text: "/stop", Entities: entities(len("/stop"))}}))
+	req.False(m.Matches(&tgbotapi.Update{Message: &tgbotapi.Message{Text: "/other", Entities: entities(len("/other"))}}))
+}
+
+func TestAnyMatcher_PropagatesChildCompileError(t *testing.T) {
+	req := require.New(t)
+	_, err := AnyMatcher{Children: []Matcher{RegexMatcher{Pattern: "("}}}.Compile()
+	req.Error(err)
+}
+
+func TestNotMatcher(t *testing.T) {
+	req := require.New(t)
+
+	m, err := NotMatcher{Child: CommandMatcher{Name: "start"}}.Compile()
+	req.NoError(err)
+
+	req.False(m.Matches(&tgbotapi.Update{Message: &tgbotapi.Message{Text: "/start", Entities: entities(len("/start"))}}))
+	req.True(m.Matches(&tgbotapi.Update{Message: &tgbotapi.Message{Text: "hello"}}))
+}
+
+func TestNotMatcher_RequiresChild(t *testing.T) {
+	req := require.New(t)
+	_, err := NotMatcher{}.Compile()
+	req.Error(err)
+}
@@ -2,21 +2,101 @@ package proxy
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/bbralion/CTFloodBot/internal/genproto"
+	"github.com/bbralion/CTFloodBot/internal/models"
 	"github.com/bbralion/CTFloodBot/pkg/auth"
+	"github.com/bbralion/CTFloodBot/pkg/observability"
 	"github.com/bbralion/CTFloodBot/pkg/services"
 	"github.com/go-logr/logr"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
 )
 
+// MultiplexerServiceName is the service name genproto.MultiplexerServiceServer is
+// registered under, used to key its per-service grpc.health.v1.Health status.
+const MultiplexerServiceName = "mux.MultiplexerService"
+
+// DefaultEmptyHandlersTimeout is how long MultiplexerServiceName may have zero
+// connected handlers before its health status flips to NOT_SERVING.
+const DefaultEmptyHandlersTimeout = time.Minute
+
+// ActionExecutor performs an Action a connected handler sent (sendMessage,
+// answerCallbackQuery, ...) against the real Telegram Bot API on its behalf, so the
+// handler never needs its own bot token. RegisterHandler relays the returned
+// ActionResult back to the handler, keyed by the Action's CorrelationId.
+type ActionExecutor interface {
+	Execute(ctx context.Context, action *genproto.Action) *genproto.ActionResult
+}
+
+// handlerKey identifies a single live RegisterHandler registration, unique for the
+// lifetime of a GRPC, mirroring services.mapMux's own muxKey.
+type handlerKey uint64
+
+// proxyHandler is one entry in GRPC's registry: a connected handler's compiled
+// matcher alongside the stream Serve delivers matching updates on. sendMu
+// serializes that Send against the ActionResult replies RegisterHandler's own
+// receive loop sends on the same stream, since grpc-go forbids concurrent Send
+// calls on one stream from different goroutines.
+type proxyHandler struct {
+	matcher models.CompiledMatcher
+	stream  genproto.MultiplexerService_RegisterHandlerServer
+
+	sendMu sync.Mutex
+}
+
+func (h *proxyHandler) send(msg *genproto.HandlerMessage) error {
+	h.sendMu.Lock()
+	defer h.sendMu.Unlock()
+	return h.stream.Send(msg)
+}
+
 type GRPC struct {
 	genproto.UnimplementedMultiplexerServiceServer
 	AdvertisedHTTPEndpoint string
 	Addr                   string
 	Logger                 logr.Logger
 	AuthProvider           services.Authenticator
+	// ActionExecutor executes Actions handlers send over RegisterHandler. Every
+	// Action is rejected with an error ActionResult if unset.
+	ActionExecutor ActionExecutor
+	// Observability adds OTel tracing and Prometheus metrics around every RPC.
+	// If nil, a default one is used.
+	Observability *observability.Observability
+	// EmptyHandlersTimeout overrides DefaultEmptyHandlersTimeout.
+	EmptyHandlersTimeout time.Duration
+
+	healthOnce     sync.Once
+	health         *health.Server
+	server         *grpc.Server
+	activeHandlers int32
+	emptySince     atomic.Value // time.Time
+
+	registryMu sync.RWMutex
+	registry   map[handlerKey]*proxyHandler
+	nextKey    uint64
+}
+
+// healthServer lazily initializes the health server, so Healthz can be mounted
+// on the HTTP endpoint before ListenAndServe has been called.
+func (p *GRPC) healthServer() *health.Server {
+	p.healthOnce.Do(func() {
+		p.health = health.NewServer()
+		p.health.SetServingStatus(MultiplexerServiceName, healthpb.HealthCheckResponse_NOT_SERVING)
+	})
+	return p.health
 }
 
 func (p *GRPC) ListenAndServe() error {
@@ -24,6 +104,28 @@ func (p *GRPC) ListenAndServe() error {
 		return errors.New("logger, auth provider and the advertised http endpoint must be set")
 	}
 
+	listener, err := net.Listen("tcp", p.Addr)
+	if err != nil {
+		return err
+	}
+
+	p.server = p.setupGRPC()
+	p.healthServer().SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	p.healthServer().SetServingStatus(MultiplexerServiceName, healthpb.HealthCheckResponse_SERVING)
+	p.markHandlersEmpty()
+	go p.watchEmptyHandlers()
+
+	return p.server.Serve(listener)
+}
+
+// Shutdown gracefully stops the gRPC server, reporting NOT_SERVING on the health
+// server for the duration of the shutdown.
+func (p *GRPC) Shutdown(context.Context) error {
+	p.healthServer().SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	p.healthServer().SetServingStatus(MultiplexerServiceName, healthpb.HealthCheckResponse_NOT_SERVING)
+	if p.server != nil {
+		p.server.GracefulStop()
+	}
 	return nil
 }
 
@@ -35,17 +137,205 @@ func (p *GRPC) GetConfig(context.Context, *genproto.ConfigRequest) (*genproto.Co
 	}, nil
 }
 
-func (p *GRPC) RegisterHandler(*genproto.RegisterRequest, genproto.MultiplexerService_RegisterHandlerServer) error {
-	return nil
+func (p *GRPC) RegisterHandler(stream genproto.MultiplexerService_RegisterHandlerServer) error {
+	msg, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	reg, ok := msg.Payload.(*genproto.HandlerMessage_Register)
+	if !ok {
+		return status.Error(codes.InvalidArgument, "first frame on RegisterHandler must be a RegisterRequest")
+	}
+
+	client, ok := auth.ClientFromContext(stream.Context())
+	if !ok {
+		return status.Error(codes.Unauthenticated, "no authenticated client in context")
+	}
+	// Only a Restricted client needs its matchers reduced to explicit commands --
+	// an unrestricted one already bypasses every Rights-based check, same as
+	// Allowed's own escape hatch, so there's nothing to deny-by-default here.
+	if client.Restricted() {
+		cmds, err := commandNames(reg.Register)
+		if err != nil {
+			return status.Errorf(codes.PermissionDenied, "client %q: %v", client.Name, err)
+		}
+		for _, cmd := range cmds {
+			if !client.AllowsCommand(cmd) {
+				return status.Errorf(codes.PermissionDenied, "client %q is not permitted to register for command %q", client.Name, cmd)
+			}
+		}
+	}
+
+	// Matchers are compiled once here rather than per-update, so a handler's routing
+	// rules are validated (and any invalid regex/unknown oneof tag rejected) as soon
+	// as it connects instead of silently never matching anything.
+	matcher, err := compileRegisterMatchers(reg.Register)
+	if err != nil {
+		return err
+	}
+
+	h := &proxyHandler{matcher: matcher, stream: stream}
+	key := p.register(h)
+	defer p.unregister(key)
+
+	atomic.AddInt32(&p.activeHandlers, 1)
+	p.healthServer().SetServingStatus(MultiplexerServiceName, healthpb.HealthCheckResponse_SERVING)
+
+	defer func() {
+		if atomic.AddInt32(&p.activeHandlers, -1) == 0 {
+			p.markHandlersEmpty()
+		}
+	}()
+
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) || status.Code(err) == codes.Canceled {
+				return nil
+			}
+			return err
+		}
+
+		action, ok := msg.Payload.(*genproto.HandlerMessage_Action)
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, "expected an Action frame, got %T", msg.Payload)
+		}
+
+		result := p.executeAction(stream.Context(), action.Action)
+		if err := h.send(&genproto.HandlerMessage{Payload: &genproto.HandlerMessage_ActionResult{ActionResult: result}}); err != nil {
+			return err
+		}
+	}
+}
+
+// register adds h to the registry, returning the key Serve and unregister use to
+// find and remove it again.
+func (p *GRPC) register(h *proxyHandler) handlerKey {
+	p.registryMu.Lock()
+	defer p.registryMu.Unlock()
+
+	if p.registry == nil {
+		p.registry = make(map[handlerKey]*proxyHandler)
+	}
+	key := handlerKey(atomic.AddUint64(&p.nextKey, 1))
+	p.registry[key] = h
+	return key
+}
+
+func (p *GRPC) unregister(key handlerKey) {
+	p.registryMu.Lock()
+	defer p.registryMu.Unlock()
+	delete(p.registry, key)
+}
+
+// executeAction runs action through p.ActionExecutor, or reports it as failed if
+// none is configured, so a handler's Send never blocks forever waiting on a result
+// that was silently dropped.
+func (p *GRPC) executeAction(ctx context.Context, action *genproto.Action) *genproto.ActionResult {
+	if p.ActionExecutor == nil {
+		return &genproto.ActionResult{CorrelationId: action.CorrelationId, Error: "proxy has no action executor configured"}
+	}
+	return p.ActionExecutor.Execute(ctx, action)
+}
+
+// Serve fans update out to every connected handler whose compiled matcher accepts
+// it, the gRPC registration API's equivalent of services.Multiplexer.Serve for the
+// HTTP one. It keeps its own registry rather than sharing Multiplexer's, since its
+// matchers come from the richer structured matcher DSL (internal/models.Matcher)
+// rather than Multiplexer's plain regexes.
+func (p *GRPC) Serve(update tgbotapi.Update) {
+	raw, err := json.Marshal(update)
+	if err != nil {
+		p.Logger.Error(err, "marshaling update for handler delivery")
+		return
+	}
+
+	p.registryMu.RLock()
+	handlers := make([]*proxyHandler, 0, len(p.registry))
+	for _, h := range p.registry {
+		handlers = append(handlers, h)
+	}
+	p.registryMu.RUnlock()
+
+	for _, h := range handlers {
+		if !h.matcher.Matches(&update) {
+			continue
+		}
+		msg := &genproto.HandlerMessage{Payload: &genproto.HandlerMessage_Update{Update: &genproto.Update{Json: raw}}}
+		if err := h.send(msg); err != nil {
+			p.Logger.Error(err, "delivering update to handler")
+		}
+	}
+}
+
+// markHandlersEmpty records the moment MultiplexerServiceName became empty of
+// connected handlers, for watchEmptyHandlers to act on once it's been empty for
+// longer than EmptyHandlersTimeout.
+func (p *GRPC) markHandlersEmpty() {
+	p.emptySince.Store(time.Now())
+}
+
+func (p *GRPC) watchEmptyHandlers() {
+	timeout := p.EmptyHandlersTimeout
+	if timeout <= 0 {
+		timeout = DefaultEmptyHandlersTimeout
+	}
+
+	ticker := time.NewTicker(timeout / 4)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if atomic.LoadInt32(&p.activeHandlers) > 0 {
+			continue
+		}
+		since, _ := p.emptySince.Load().(time.Time)
+		if !since.IsZero() && time.Since(since) >= timeout {
+			p.healthServer().SetServingStatus(MultiplexerServiceName, healthpb.HealthCheckResponse_NOT_SERVING)
+		}
+	}
+}
+
+// Healthz serves the grpc.health.v1.Health overall status as grpc-gateway-style
+// JSON over plain HTTP (200 when SERVING, 503 otherwise), so the health server is
+// also reachable from the advertised HTTP endpoint without speaking gRPC.
+func (p *GRPC) Healthz() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		resp, err := p.healthServer().Check(r.Context(), &healthpb.HealthCheckRequest{})
+		if err != nil || resp.Status != healthpb.HealthCheckResponse_SERVING {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}
+}
+
+// observability lazily initializes Observability, mirroring healthServer's laziness
+// so Metrics can be mounted on the HTTP endpoint before ListenAndServe is called.
+func (p *GRPC) observability() *observability.Observability {
+	if p.Observability == nil {
+		p.Observability = observability.New()
+	}
+	return p.Observability
+}
+
+// Metrics serves the Prometheus metrics for this GRPC's RPCs, suitable for mounting
+// at /metrics on the advertised HTTP endpoint alongside Healthz.
+func (p *GRPC) Metrics() http.Handler {
+	return p.observability().MetricsHandler()
 }
 
 func (p *GRPC) setupGRPC() *grpc.Server {
+	obs := p.observability()
 	interceptor := auth.NewGRPCServerInterceptor(p.Logger, p.AuthProvider)
 	server := grpc.NewServer(
-		grpc.UnaryInterceptor(interceptor.Unary()),
-		grpc.StreamInterceptor(interceptor.Stream()),
+		grpc.UnaryInterceptor(obs.UnaryServerInterceptor(p.Logger, interceptor.Unary())),
+		grpc.StreamInterceptor(obs.StreamServerInterceptor(p.Logger, interceptor.Stream())),
 	)
 
 	genproto.RegisterMultiplexerServiceServer(server, p)
+
+	healthpb.RegisterHealthServer(server, p.healthServer())
+
 	return server
 }
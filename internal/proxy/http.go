@@ -2,11 +2,15 @@ package proxy
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
 	"regexp"
+	"strconv"
+	"strings"
 	"sync/atomic"
 	"time"
 
@@ -15,6 +19,7 @@ import (
 	"github.com/go-logr/logr"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
 	"github.com/justinas/alice"
+	"golang.org/x/net/proxy"
 )
 
 // DefaultRequestTimeout is the default timeout to be used for making requests to the telegram API
@@ -31,9 +36,16 @@ type HTTP struct {
 	AuthProvider services.Authenticator
 	// If set only paths in the allowlist will be allowed
 	Allowlist internal.Allowlist
+	// If set, caps requests per-client (or per-remote-IP if unauthenticated) against
+	// RateLimiter's budget, rejecting the rest with 429.
+	RateLimiter internal.RateLimiter
 	// Transport is the transport to use for making requests to the telegram API.
 	// http.DefaultTransport will be used by default
 	Transport *http.Transport
+	// ProxyURL, if set, routes outbound requests to the telegram API through an
+	// egress proxy instead of dialing it directly. Supported schemes are "http",
+	// "https" (CONNECT) and "socks5".
+	ProxyURL string
 	// Telegram API token
 	Token string
 	// Telegram API endpoint to use, may be another proxy
@@ -55,7 +67,9 @@ func (p *HTTP) ListenAndServe() error {
 	}
 
 	p.Logger = p.Logger.WithName("http")
-	p.setDefaults()
+	if err := p.setDefaults(); err != nil {
+		return err
+	}
 
 	// TODO: implement proper handling of special commands such as setMyCommands
 	handler := httputil.ReverseProxy{
@@ -74,11 +88,50 @@ func (p *HTTP) ListenAndServe() error {
 		Transport: p.Transport,
 	}
 
-	p.Handler = alice.New(p.PanicMiddleware, p.RequestIDMiddleware, p.LoggingMiddleware, p.AuthMiddleware, p.AllowPathMiddleware).Then(&handler)
+	p.Handler = alice.New(p.PanicMiddleware, p.RequestIDMiddleware, p.LoggingMiddleware, p.AuthMiddleware, p.RateLimitMiddleware, p.AllowPathMiddleware).Then(&handler)
 	return p.ListenAndServe()
 }
 
-func (p *HTTP) setDefaults() {
+// SetWebhook calls the upstream Telegram API's setWebhook with webhookURL and,
+// if non-empty, secretToken, so services.WebhookUpdateProvider starts receiving
+// pushed updates instead of GetUpdatesChan having to be long-polled. The vendored
+// tgbotapi.WebhookConfig predates secret_token support, so this makes the request
+// directly rather than going through *tgbotapi.BotAPI.SetWebhook.
+func (p *HTTP) SetWebhook(ctx context.Context, webhookURL, secretToken string) error {
+	if err := p.setDefaults(); err != nil {
+		return err
+	}
+
+	v := url.Values{"url": {webhookURL}}
+	if secretToken != "" {
+		v.Set("secret_token", secretToken)
+	}
+
+	reqURL := fmt.Sprintf(p.Endpoint, p.Token, "setWebhook")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, strings.NewReader(v.Encode()))
+	if err != nil {
+		return fmt.Errorf("building setWebhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	client := &http.Client{Transport: p.Transport}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("calling setWebhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var apiResp tgbotapi.APIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return fmt.Errorf("decoding setWebhook response: %w", err)
+	}
+	if !apiResp.Ok {
+		return fmt.Errorf("setWebhook failed: %s", apiResp.Description)
+	}
+	return nil
+}
+
+func (p *HTTP) setDefaults() error {
 	if p.Transport == nil {
 		p.Transport = &http.Transport{}
 	}
@@ -89,6 +142,68 @@ func (p *HTTP) setDefaults() {
 	if p.Endpoint == "" {
 		p.Endpoint = tgbotapi.APIEndpoint
 	}
+
+	if p.ProxyURL != "" {
+		if err := configureProxy(p.Transport, p.ProxyURL); err != nil {
+			return fmt.Errorf("invalid proxy url specified: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// configureProxy points t at the egress proxy described by rawURL: an http(s) URL
+// sets t.Proxy for HTTP CONNECT, while a socks5 URL replaces t.DialContext with a
+// dialer going through that SOCKS5 proxy.
+func configureProxy(t *http.Transport, rawURL string) error {
+	proxyURL, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+
+	switch proxyURL.Scheme {
+	case "http", "https":
+		t.Proxy = http.ProxyURL(proxyURL)
+	case "socks5":
+		dialer, err := proxy.FromURL(proxyURL, proxy.Direct)
+		if err != nil {
+			return fmt.Errorf("building socks5 dialer: %w", err)
+		}
+		t.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return dialViaProxy(ctx, dialer, network, addr)
+		}
+	default:
+		return fmt.Errorf("unsupported scheme %q, must be http, https or socks5", proxyURL.Scheme)
+	}
+
+	return nil
+}
+
+// dialViaProxy uses DialContext when dialer implements proxy.ContextDialer (as the
+// socks5 dialer returned by proxy.FromURL does); otherwise it runs the context-less
+// dialer.Dial in a goroutine so ctx cancellation is still honored.
+func dialViaProxy(ctx context.Context, dialer proxy.Dialer, network, addr string) (net.Conn, error) {
+	if ctxDialer, ok := dialer.(proxy.ContextDialer); ok {
+		return ctxDialer.DialContext(ctx, network, addr)
+	}
+
+	var conn net.Conn
+	var err error
+	done := make(chan struct{})
+	go func() {
+		conn, err = dialer.Dial(network, addr)
+		close(done)
+		if conn != nil && ctx.Err() != nil {
+			conn.Close()
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-done:
+		return conn, err
+	}
 }
 
 type requestIDCtxKey struct{}
@@ -132,6 +247,10 @@ func (p *HTTP) AuthMiddleware(next http.Handler) http.Handler {
 				w.WriteHeader(http.StatusUnauthorized)
 				return
 			}
+			if !client.Allowed(r.Method, groups[2]) {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
 			authenticatedReq := r.WithContext(context.WithValue(r.Context(), clientCtxKey{}, client))
 			authenticatedReq.URL.Path = groups[2]
 			next.ServeHTTP(w, authenticatedReq)
@@ -162,6 +281,43 @@ func (p *HTTP) LoggingMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// RateLimitMiddleware enforces p.RateLimiter, keyed by the authenticated client
+// (as set by AuthMiddleware) or, absent one, the request's remote IP, rejecting
+// over-budget requests with 429 and a Retry-After header. It must run after
+// AuthMiddleware so the request path has already been rewritten to the plain
+// telegram method (e.g. "/sendMessage"), which RateLimiter uses to apply a
+// tighter budget to message-sending calls.
+func (p *HTTP) RateLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if p.RateLimiter == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key := rateLimitKey(r)
+		if ok, retryAfter := p.RateLimiter.Allow(key, r.URL.Path); !ok {
+			p.Logger.Info("rejected request over rate limit", "key", key, "path", r.URL.Path, "retry_after", retryAfter, "request_id", requestID(r))
+			w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Round(time.Second).Seconds())+1))
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// rateLimitKey returns the client set by AuthMiddleware or, if unauthenticated,
+// the request's remote IP without its port.
+func rateLimitKey(r *http.Request) string {
+	if client, ok := r.Context().Value(clientCtxKey{}).(services.Client); ok {
+		return client.Name
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
 func (p *HTTP) AllowPathMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if p.Allowlist != nil && !p.Allowlist.Allowed(r.URL.Path) {
@@ -0,0 +1,164 @@
+package proxy
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/bbralion/CTFloodBot/internal/genproto"
+	"github.com/bbralion/CTFloodBot/internal/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// legacyCommandPattern recognizes the "^/command$"-shaped strings the old
+// []string matchers used for plain commands, so they can be translated into a
+// CommandMatch instead of a RegexMatch.
+var legacyCommandPattern = regexp.MustCompile(`^\^/(\w+)\$$`)
+
+// compileRegisterMatchers builds a single CompiledMatcher out of a RegisterRequest,
+// preferring its structured_matchers and falling back to translating the deprecated
+// string matchers otherwise. It is called once per RegisterHandler stream, so
+// the result is reused for every update instead of being re-parsed each time.
+func compileRegisterMatchers(req *genproto.RegisterRequest) (models.CompiledMatcher, error) {
+	var matcher models.Matcher
+	if len(req.StructuredMatchers) > 0 {
+		translated, err := translateMatchers(req.StructuredMatchers)
+		if err != nil {
+			return nil, err
+		}
+		matcher = models.AnyMatcher{Children: translated}
+	} else {
+		translated, err := translateLegacyMatchers(req.Matchers)
+		if err != nil {
+			return nil, err
+		}
+		matcher = models.AnyMatcher{Children: translated}
+	}
+
+	compiled, err := matcher.Compile()
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "compiling matchers: %v", err)
+	}
+	return compiled, nil
+}
+
+func translateMatchers(in []*genproto.Matcher) ([]models.Matcher, error) {
+	out := make([]models.Matcher, len(in))
+	for i, m := range in {
+		translated, err := translateMatcher(m)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = translated
+	}
+	return out, nil
+}
+
+func translateMatcher(m *genproto.Matcher) (models.Matcher, error) {
+	switch kind := m.Kind.(type) {
+	case *genproto.Matcher_Command:
+		return models.CommandMatcher{Name: kind.Command.Name, BotUsernameRequired: kind.Command.BotUsernameRequired}, nil
+	case *genproto.Matcher_Regex:
+		return models.RegexMatcher{Pattern: kind.Regex.Pattern, Field: translateField(kind.Regex.Field)}, nil
+	case *genproto.Matcher_CallbackData:
+		return models.CallbackDataMatcher{Prefix: kind.CallbackData.Prefix}, nil
+	case *genproto.Matcher_MessageType:
+		return models.MessageTypeMatcher{Types: kind.MessageType.Types}, nil
+	case *genproto.Matcher_ChatType:
+		return models.ChatTypeMatcher{Types: kind.ChatType.Types}, nil
+	case *genproto.Matcher_All:
+		return models.AllMatcher{}, nil
+	case *genproto.Matcher_Any:
+		children, err := translateMatchers(kind.Any.Children)
+		if err != nil {
+			return nil, err
+		}
+		return models.AnyMatcher{Children: children}, nil
+	case *genproto.Matcher_Not:
+		child, err := translateMatcher(kind.Not.Child)
+		if err != nil {
+			return nil, err
+		}
+		return models.NotMatcher{Child: child}, nil
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown matcher kind %T", kind)
+	}
+}
+
+// commandNames reduces req's matchers down to the literal command names a scoped
+// client's permissions can be checked against, so RegisterHandler can enforce
+// deny-by-default command scoping. It mirrors compileRegisterMatchers' precedence
+// -- structured_matchers if present, the deprecated string matchers otherwise --
+// so the commands checked here are exactly the commands that will actually be
+// routed on. Any matcher subtree that can't be expressed purely as a set of
+// commands (a bare regex/message-type/chat-type/callback-data leaf, an All
+// wildcard, or a Not -- which inverts its child's meaning rather than narrowing
+// it) is reported as an error instead of silently contributing no names, since the
+// latter would let such a matcher through a scoped client's check unrestricted.
+func commandNames(req *genproto.RegisterRequest) ([]string, error) {
+	if len(req.StructuredMatchers) > 0 {
+		var names []string
+		for _, m := range req.StructuredMatchers {
+			ns, err := commandNamesInMatcher(m)
+			if err != nil {
+				return nil, err
+			}
+			names = append(names, ns...)
+		}
+		return names, nil
+	}
+
+	var names []string
+	for _, s := range req.Matchers {
+		groups := legacyCommandPattern.FindStringSubmatch(s)
+		if groups == nil {
+			return nil, fmt.Errorf("legacy matcher %q does not resolve to an explicit command", s)
+		}
+		names = append(names, groups[1])
+	}
+	return names, nil
+}
+
+func commandNamesInMatcher(m *genproto.Matcher) ([]string, error) {
+	switch kind := m.Kind.(type) {
+	case *genproto.Matcher_Command:
+		return []string{kind.Command.Name}, nil
+	case *genproto.Matcher_Any:
+		var names []string
+		for _, c := range kind.Any.Children {
+			ns, err := commandNamesInMatcher(c)
+			if err != nil {
+				return nil, err
+			}
+			names = append(names, ns...)
+		}
+		return names, nil
+	default:
+		return nil, fmt.Errorf("matcher kind %T does not resolve to an explicit command", kind)
+	}
+}
+
+func translateField(field genproto.MatchField) models.MatchField {
+	if field == genproto.MatchField_MATCH_FIELD_CAPTION {
+		return models.FieldCaption
+	}
+	return models.FieldText
+}
+
+// translateLegacyMatchers converts the deprecated []string matchers into their
+// structured equivalents: a string of the form "^/command$" becomes a CommandMatch,
+// anything else becomes a RegexMatch against the message text.
+func translateLegacyMatchers(in []string) ([]models.Matcher, error) {
+	out := make([]models.Matcher, len(in))
+	for i, s := range in {
+		if groups := legacyCommandPattern.FindStringSubmatch(s); groups != nil {
+			out[i] = models.CommandMatcher{Name: groups[1]}
+			continue
+		}
+		if len(s) > models.MaxRegexPatternLength {
+			return nil, status.Errorf(codes.InvalidArgument, "legacy matcher %q: %v", s, fmt.Errorf("pattern exceeds %d characters", models.MaxRegexPatternLength))
+		}
+		out[i] = models.RegexMatcher{Pattern: s, Field: models.FieldText}
+	}
+	return out, nil
+}
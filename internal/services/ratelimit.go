@@ -0,0 +1,227 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RateLimiter decides whether a request identified by key (an authenticated
+// client's name, or a remote IP as a fallback) and addressed to path (a telegram
+// API method, e.g. "/sendMessage") should be let through. On rejection, retryAfter
+// is how long the caller should wait before its next attempt.
+type RateLimiter interface {
+	Allow(key, path string) (ok bool, retryAfter time.Duration)
+}
+
+// rate is a parsed "N-S"/"N-M"/"N-H" budget: count requests per unit.
+type rate struct {
+	count int
+	per   time.Duration
+}
+
+// ParseRate parses a budget of the form "<count>-<unit>", unit being S, M or H for
+// second, minute or hour respectively (e.g. "30-S" is 30 requests per second).
+func ParseRate(spec string) (count int, per time.Duration, err error) {
+	if len(spec) < 3 || spec[len(spec)-2] != '-' {
+		return 0, 0, fmt.Errorf("rate limit spec %q must look like <count>-<unit>", spec)
+	}
+
+	count, err = strconv.Atoi(spec[:len(spec)-2])
+	if err != nil || count <= 0 {
+		return 0, 0, fmt.Errorf("rate limit spec %q: count must be a positive integer", spec)
+	}
+
+	switch unit := spec[len(spec)-1]; unit {
+	case 'S', 's':
+		per = time.Second
+	case 'M', 'm':
+		per = time.Minute
+	case 'H', 'h':
+		per = time.Hour
+	default:
+		return 0, 0, fmt.Errorf("rate limit spec %q: unknown unit %q, must be S, M or H", spec, unit)
+	}
+
+	return count, per, nil
+}
+
+// sendClassPaths are the telegram methods enforcing stricter per-chat rate limits
+// upstream, so tokenBucketRateLimiter applies the tighter of its two budgets to them.
+var sendClassPaths = map[string]struct{}{
+	"sendMessage":    {},
+	"sendPhoto":      {},
+	"sendAudio":      {},
+	"sendDocument":   {},
+	"sendVideo":      {},
+	"sendAnimation":  {},
+	"sendVoice":      {},
+	"sendVideoNote":  {},
+	"sendMediaGroup": {},
+	"sendLocation":   {},
+	"sendVenue":      {},
+	"sendContact":    {},
+	"sendPoll":       {},
+	"sendDice":       {},
+	"sendSticker":    {},
+	"copyMessage":    {},
+	"forwardMessage": {},
+}
+
+// isSendClassPath reports whether path (as rewritten by proxy.HTTP.AuthMiddleware,
+// e.g. "/sendMessage") names a send-class method.
+func isSendClassPath(path string) bool {
+	if len(path) > 0 && path[0] == '/' {
+		path = path[1:]
+	}
+	_, ok := sendClassPaths[path]
+	return ok
+}
+
+// bucket is a single continuously-refilling token bucket, protected by its own
+// mutex since many of them are accessed concurrently, one per rate-limited key.
+type bucket struct {
+	mu         sync.Mutex
+	tokens     float64
+	capacity   float64
+	refillRate float64 // tokens per second
+	lastRefill time.Time
+}
+
+func newBucket(count int, per time.Duration) *bucket {
+	capacity := float64(count)
+	return &bucket{
+		tokens:     capacity,
+		capacity:   capacity,
+		refillRate: capacity / per.Seconds(),
+		lastRefill: time.Now(),
+	}
+}
+
+func (b *bucket) take() (ok bool, retryAfter time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens = min(b.capacity, b.tokens+now.Sub(b.lastRefill).Seconds()*b.refillRate)
+	b.lastRefill = now
+
+	if b.tokens < 1 {
+		missing := 1 - b.tokens
+		return false, time.Duration(missing / b.refillRate * float64(time.Second))
+	}
+
+	b.tokens--
+	return true, 0
+}
+
+// refund returns a token taken in error, e.g. when a later check in the same
+// request fails and the earlier bucket's consumption should not count against it.
+func (b *bucket) refund() {
+	b.mu.Lock()
+	b.tokens = min(b.capacity, b.tokens+1)
+	b.mu.Unlock()
+}
+
+func min(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// TokenBucketRateLimiterOption configures a TokenBucketRateLimiter created by
+// NewTokenBucketRateLimiter.
+type TokenBucketRateLimiterOption func(*tokenBucketRateLimiter)
+
+// WithRateLimiterRegisterer registers the limiter's Prometheus metrics with reg
+// instead of the default registry, so tests can inject a throwaway
+// prometheus.Registry.
+func WithRateLimiterRegisterer(reg prometheus.Registerer) TokenBucketRateLimiterOption {
+	return func(l *tokenBucketRateLimiter) { l.register(reg) }
+}
+
+type tokenBucketRateLimiter struct {
+	global rate
+	tight  rate
+
+	mu           sync.Mutex
+	globalBucket map[string]*bucket
+	tightBucket  map[string]*bucket
+
+	decisionsTotal *prometheus.CounterVec
+}
+
+// NewTokenBucketRateLimiter returns a RateLimiter enforcing globalRate against
+// every request and, additionally, tightRate against requests to send-class
+// methods (sendMessage, sendPhoto and similar), both parsed by ParseRate.
+func NewTokenBucketRateLimiter(globalRate, tightRate string, opts ...TokenBucketRateLimiterOption) (RateLimiter, error) {
+	globalCount, globalPer, err := ParseRate(globalRate)
+	if err != nil {
+		return nil, err
+	}
+	tightCount, tightPer, err := ParseRate(tightRate)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &tokenBucketRateLimiter{
+		global:       rate{globalCount, globalPer},
+		tight:        rate{tightCount, tightPer},
+		globalBucket: make(map[string]*bucket),
+		tightBucket:  make(map[string]*bucket),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	if l.decisionsTotal == nil {
+		l.register(prometheus.DefaultRegisterer)
+	}
+	return l, nil
+}
+
+func (l *tokenBucketRateLimiter) register(reg prometheus.Registerer) {
+	l.decisionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "proxy_rate_limit_decisions_total",
+		Help: "Total number of telegram API proxy requests evaluated against the rate limiter, by result.",
+	}, []string{"result"})
+
+	reg.MustRegister(l.decisionsTotal)
+}
+
+func (l *tokenBucketRateLimiter) bucketFor(buckets map[string]*bucket, key string, r rate) *bucket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := buckets[key]
+	if !ok {
+		b = newBucket(r.count, r.per)
+		buckets[key] = b
+	}
+	return b
+}
+
+func (l *tokenBucketRateLimiter) Allow(key, path string) (bool, time.Duration) {
+	global := l.bucketFor(l.globalBucket, key, l.global)
+	ok, retryAfter := global.take()
+	if !ok {
+		l.decisionsTotal.WithLabelValues("rejected").Inc()
+		return false, retryAfter
+	}
+
+	if isSendClassPath(path) {
+		tight := l.bucketFor(l.tightBucket, key, l.tight)
+		ok, retryAfter := tight.take()
+		if !ok {
+			global.refund()
+			l.decisionsTotal.WithLabelValues("rejected").Inc()
+			return false, retryAfter
+		}
+	}
+
+	l.decisionsTotal.WithLabelValues("accepted").Inc()
+	return true, 0
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr bool
+		count   int
+		per     time.Duration
+	}{
+		{name: "per second", spec: "30-S", count: 30, per: time.Second},
+		{name: "per minute", spec: "20-M", count: 20, per: time.Minute},
+		{name: "per hour", spec: "5-H", count: 5, per: time.Hour},
+		{name: "lowercase unit", spec: "5-s", count: 5, per: time.Second},
+		{name: "missing separator", spec: "30S", wantErr: true},
+		{name: "zero count", spec: "0-S", wantErr: true},
+		{name: "non-numeric count", spec: "abc-S", wantErr: true},
+		{name: "unknown unit", spec: "5-D", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			req := require.New(t)
+
+			count, per, err := ParseRate(tt.spec)
+			if tt.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+			req.Equal(tt.count, count)
+			req.Equal(tt.per, per)
+		})
+	}
+}
+
+func TestTokenBucketRateLimiter_AllowsWithinBudget(t *testing.T) {
+	req := require.New(t)
+
+	l, err := NewTokenBucketRateLimiter("2-S", "1-S", WithRateLimiterRegisterer(prometheus.NewRegistry()))
+	req.NoError(err)
+
+	ok, _ := l.Allow("client", "/getMe")
+	req.True(ok)
+	ok, _ = l.Allow("client", "/getMe")
+	req.True(ok)
+}
+
+func TestTokenBucketRateLimiter_RejectsOverBudget(t *testing.T) {
+	req := require.New(t)
+
+	l, err := NewTokenBucketRateLimiter("1-S", "1-S", WithRateLimiterRegisterer(prometheus.NewRegistry()))
+	req.NoError(err)
+
+	ok, _ := l.Allow("client", "/getMe")
+	req.True(ok)
+
+	ok, retryAfter := l.Allow("client", "/getMe")
+	req.False(ok)
+	req.Greater(retryAfter, time.Duration(0))
+}
+
+func TestTokenBucketRateLimiter_SeparateKeysHaveSeparateBudgets(t *testing.T) {
+	req := require.New(t)
+
+	l, err := NewTokenBucketRateLimiter("1-S", "1-S", WithRateLimiterRegisterer(prometheus.NewRegistry()))
+	req.NoError(err)
+
+	ok, _ := l.Allow("client-a", "/getMe")
+	req.True(ok)
+	ok, _ = l.Allow("client-b", "/getMe")
+	req.True(ok)
+}
+
+func TestTokenBucketRateLimiter_TightBudgetAppliesOnlyToSendClassPaths(t *testing.T) {
+	req := require.New(t)
+
+	l, err := NewTokenBucketRateLimiter("10-S", "1-S", WithRateLimiterRegisterer(prometheus.NewRegistry()))
+	req.NoError(err)
+
+	ok, _ := l.Allow("client", "/sendMessage")
+	req.True(ok)
+	ok, _ = l.Allow("client", "/sendMessage")
+	req.False(ok, "second sendMessage within the same second should be rejected by the tight budget")
+
+	// getMe isn't send-class, so it's still governed only by the wider global budget.
+	ok, _ = l.Allow("client", "/getMe")
+	req.True(ok)
+}
+
+func TestTokenBucketRateLimiter_InvalidRateIsRejected(t *testing.T) {
+	req := require.New(t)
+
+	_, err := NewTokenBucketRateLimiter("bad", "1-S")
+	req.Error(err)
+
+	_, err = NewTokenBucketRateLimiter("1-S", "bad")
+	req.Error(err)
+}
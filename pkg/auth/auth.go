@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"path"
 
 	"github.com/bbralion/CTFloodBot/pkg/services"
 	"github.com/go-logr/logr"
@@ -13,6 +14,11 @@ import (
 
 const tokenKey = "authorization"
 
+// grpcRightsMethod is the pseudo-HTTP-method under which gRPC full method names are
+// checked in Client.Rights (e.g. {"RPC": {"/mux.MultiplexerService/*"}}), since
+// gRPC has no equivalent of an HTTP verb to key on.
+const grpcRightsMethod = "RPC"
+
 type GRPCClientInterceptor string
 
 // NewGRPCClientInterceptor creates a new gRPC client interceptor which uses the given token
@@ -51,32 +57,87 @@ func (t GRPCClientInterceptor) Stream() grpc.StreamClientInterceptor {
 	}
 }
 
+// MatchFunc reports whether an incoming call should be authenticated. A false
+// result skips authorize entirely, so e.g. health checks and reflection can be
+// exposed without a token. fullMethod is the gRPC full method name, e.g.
+// "/grpc.health.v1.Health/Check".
+type MatchFunc func(ctx context.Context, fullMethod string) bool
+
+// GRPCServerInterceptorOption configures optional behavior of a GRPCServerInterceptor.
+type GRPCServerInterceptorOption func(*GRPCServerInterceptor)
+
+// WithMatch sets the MatchFunc deciding which calls are authenticated. Every call
+// is authenticated if this is never set.
+func WithMatch(match MatchFunc) GRPCServerInterceptorOption {
+	return func(i *GRPCServerInterceptor) { i.match = match }
+}
+
+// WithSkipMethods is a convenience wrapper around WithMatch that skips
+// authentication for calls whose full method matches one of methods, compared via
+// path.Match so e.g. "/pkg.Service/*" skips a whole service.
+func WithSkipMethods(methods ...string) GRPCServerInterceptorOption {
+	return WithMatch(func(_ context.Context, fullMethod string) bool {
+		for _, m := range methods {
+			if ok, err := path.Match(m, fullMethod); err == nil && ok {
+				return false
+			}
+		}
+		return true
+	})
+}
+
 // GRPCServerInterceptor is a Unary and Stream interceptor provider which
 // uses an underlying AuthProvider for authentication of clients
 type GRPCServerInterceptor struct {
 	logger   logr.Logger
 	provider services.Authenticator
+	// match decides which calls are authenticated; every call is authenticated if
+	// it's nil.
+	match MatchFunc
 }
 
 // NewGRPCServerInterceptor returns a new gRPC server interceptor
 // which authenticates clients using the specified provider.
-func NewGRPCServerInterceptor(logger logr.Logger, provider services.Authenticator) *GRPCServerInterceptor {
-	return &GRPCServerInterceptor{logger, provider}
+func NewGRPCServerInterceptor(logger logr.Logger, provider services.Authenticator, opts ...GRPCServerInterceptorOption) *GRPCServerInterceptor {
+	i := &GRPCServerInterceptor{logger: logger, provider: provider}
+	for _, opt := range opts {
+		opt(i)
+	}
+	return i
+}
+
+// shouldAuthenticate reports whether method requires authentication under i.match.
+func (i *GRPCServerInterceptor) shouldAuthenticate(ctx context.Context, method string) bool {
+	return i.match == nil || i.match(ctx, method)
 }
 
-func (i *GRPCServerInterceptor) authorize(ctx context.Context, method string) error {
+// clientCtxKey is the typed context key under which authorize stashes the
+// authenticated services.Client, retrievable via ClientFromContext.
+type clientCtxKey struct{}
+
+// ClientFromContext retrieves the services.Client authenticated by
+// GRPCServerInterceptor for the current call, if any.
+func ClientFromContext(ctx context.Context) (services.Client, bool) {
+	client, ok := ctx.Value(clientCtxKey{}).(services.Client)
+	return client, ok
+}
+
+func (i *GRPCServerInterceptor) authorize(ctx context.Context, method string) (context.Context, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok || len(md[tokenKey]) != 1 {
-		return status.Error(codes.Unauthenticated, "must contain metadata with single auth token")
+		return ctx, status.Error(codes.Unauthenticated, "must contain metadata with single auth token")
 	}
 
 	client, err := i.provider.Authenticate(md[tokenKey][0])
 	if err != nil {
-		return status.Error(codes.Unauthenticated, err.Error())
+		return ctx, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if !client.Allowed(grpcRightsMethod, method) {
+		return ctx, status.Errorf(codes.PermissionDenied, "client %q is not permitted to call %s", client.Name, method)
 	}
 
 	i.logger.Info("gRPC request from authenticated client", "client", client, "method", method)
-	return nil
+	return context.WithValue(ctx, clientCtxKey{}, client), nil
 }
 
 // Unary returns a unary gRPC server interceptor for authentication
@@ -87,13 +148,29 @@ func (i *GRPCServerInterceptor) Unary() grpc.UnaryServerInterceptor {
 		info *grpc.UnaryServerInfo,
 		handler grpc.UnaryHandler,
 	) (interface{}, error) {
-		if err := i.authorize(ctx, info.FullMethod); err != nil {
+		if !i.shouldAuthenticate(ctx, info.FullMethod) {
+			i.logger.V(1).Info("skipping auth for unary call", "method", info.FullMethod)
+			return handler(ctx, req)
+		}
+		ctx, err := i.authorize(ctx, info.FullMethod)
+		if err != nil {
 			return nil, err
 		}
 		return handler(ctx, req)
 	}
 }
 
+// wrappedServerStream lets Stream hand the handler a ServerStream whose
+// Context() carries the authenticated client injected by authorize.
+type wrappedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *wrappedServerStream) Context() context.Context {
+	return s.ctx
+}
+
 // Stream returns a stream gRPC server interceptor for authentication
 func (i *GRPCServerInterceptor) Stream() grpc.StreamServerInterceptor {
 	return func(
@@ -102,9 +179,14 @@ func (i *GRPCServerInterceptor) Stream() grpc.StreamServerInterceptor {
 		info *grpc.StreamServerInfo,
 		handler grpc.StreamHandler,
 	) error {
-		if err := i.authorize(stream.Context(), info.FullMethod); err != nil {
+		if !i.shouldAuthenticate(stream.Context(), info.FullMethod) {
+			i.logger.V(1).Info("skipping auth for stream call", "method", info.FullMethod)
+			return handler(srv, stream)
+		}
+		ctx, err := i.authorize(stream.Context(), info.FullMethod)
+		if err != nil {
 			return err
 		}
-		return handler(srv, stream)
+		return handler(srv, &wrappedServerStream{stream, ctx})
 	}
 }
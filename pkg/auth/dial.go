@@ -0,0 +1,178 @@
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+)
+
+// TLSConfig configures transport security for the client<->proxy gRPC channel.
+// Its fields mirror config.GRPCProxy's TLS section, so it can be populated
+// directly from config and handed to ServerDialOptions/ClientDialOptions.
+type TLSConfig struct {
+	// CertFile/KeyFile are this side's certificate and key. Required on the
+	// server; optional on the client, where they enable mTLS.
+	CertFile string
+	KeyFile  string
+	// ClientCAFile, if set, is the CA bundle used by the server to verify
+	// client certificates, enabling mTLS. Ignored on the client.
+	ClientCAFile string
+	// ServerName overrides the name used for server certificate verification.
+	ServerName string
+	// InsecureSkipVerify disables server certificate verification; only for local testing.
+	InsecureSkipVerify bool
+}
+
+func (c *TLSConfig) serverCredentials() (credentials.TransportCredentials, error) {
+	if c.CertFile == "" || c.KeyFile == "" {
+		return nil, errors.New("cert file and key file must be set")
+	}
+	cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading server certificate: %w", err)
+	}
+
+	cfg := &tls.Config{Certificates: []tls.Certificate{cert}}
+	if c.ClientCAFile != "" {
+		ca, err := os.ReadFile(c.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading client CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, errors.New("no certificates found in client CA bundle")
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return credentials.NewTLS(cfg), nil
+}
+
+func (c *TLSConfig) clientCredentials() (credentials.TransportCredentials, error) {
+	cfg := &tls.Config{
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+
+	if c.CertFile != "" || c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return credentials.NewTLS(cfg), nil
+}
+
+// KeepaliveConfig configures gRPC keepalive pings for the client<->proxy
+// channel, so an idle long-lived streaming RPC (e.g. RegisterHandler) survives
+// NAT boxes and load balancers that silently drop quiet connections. Its
+// fields mirror config.GRPCProxy's Keepalive section.
+type KeepaliveConfig struct {
+	// Time is how long the connection may be idle before a keepalive ping is sent.
+	Time time.Duration
+	// Timeout is how long to wait for a ping response before closing the connection.
+	Timeout time.Duration
+	// PermitWithoutStream allows pings even when there are no active RPCs.
+	PermitWithoutStream bool
+	// MinTime is the minimum time a client must wait between pings; enforced server-side.
+	MinTime time.Duration
+	// MaxConnectionIdle closes a connection that's had no active RPCs for this long.
+	MaxConnectionIdle time.Duration
+	// MaxConnectionAge closes a connection once it's existed this long, regardless of activity.
+	MaxConnectionAge time.Duration
+}
+
+func (c *KeepaliveConfig) serverParameters() keepalive.ServerParameters {
+	return keepalive.ServerParameters{
+		MaxConnectionIdle: c.MaxConnectionIdle,
+		MaxConnectionAge:  c.MaxConnectionAge,
+		Time:              c.Time,
+		Timeout:           c.Timeout,
+	}
+}
+
+func (c *KeepaliveConfig) enforcementPolicy() keepalive.EnforcementPolicy {
+	return keepalive.EnforcementPolicy{
+		MinTime:             c.MinTime,
+		PermitWithoutStream: c.PermitWithoutStream,
+	}
+}
+
+func (c *KeepaliveConfig) clientParameters() keepalive.ClientParameters {
+	return keepalive.ClientParameters{
+		Time:                c.Time,
+		Timeout:             c.Timeout,
+		PermitWithoutStream: c.PermitWithoutStream,
+	}
+}
+
+// DialConfig bundles the optional TLS and keepalive settings shared by
+// ServerDialOptions and ClientDialOptions.
+type DialConfig struct {
+	// TLS, if non-nil, enables transport security (optionally mTLS) for the dial.
+	// If nil, the connection is made over plaintext.
+	TLS *TLSConfig
+	// Keepalive, if non-nil, enables keepalive pings/enforcement.
+	Keepalive *KeepaliveConfig
+}
+
+// ServerDialOptions bundles the transport credentials and keepalive parameters
+// described by cfg into the grpc.ServerOptions for a GRPCServerInterceptor-protected
+// server; install the interceptor itself separately via NewGRPCServerInterceptor.
+func ServerDialOptions(cfg DialConfig) ([]grpc.ServerOption, error) {
+	var opts []grpc.ServerOption
+
+	if cfg.TLS != nil {
+		creds, err := cfg.TLS.serverCredentials()
+		if err != nil {
+			return nil, fmt.Errorf("building server TLS credentials: %w", err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	if cfg.Keepalive != nil {
+		opts = append(opts,
+			grpc.KeepaliveParams(cfg.Keepalive.serverParameters()),
+			grpc.KeepaliveEnforcementPolicy(cfg.Keepalive.enforcementPolicy()),
+		)
+	}
+
+	return opts, nil
+}
+
+// ClientDialOptions bundles the transport credentials, auth token and keepalive
+// parameters described by cfg and token into the grpc.DialOptions for dialing a
+// ServerDialOptions-protected server.
+func ClientDialOptions(cfg DialConfig, token string) ([]grpc.DialOption, error) {
+	var opts []grpc.DialOption
+
+	if cfg.TLS != nil {
+		creds, err := cfg.TLS.clientCredentials()
+		if err != nil {
+			return nil, fmt.Errorf("building client TLS credentials: %w", err)
+		}
+		opts = append(opts, grpc.WithTransportCredentials(creds))
+	} else {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+
+	interceptor := NewGRPCClientInterceptor(token)
+	opts = append(opts, grpc.WithUnaryInterceptor(interceptor.Unary()), grpc.WithStreamInterceptor(interceptor.Stream()))
+
+	if cfg.Keepalive != nil {
+		opts = append(opts, grpc.WithKeepaliveParams(cfg.Keepalive.clientParameters()))
+	}
+
+	return opts, nil
+}
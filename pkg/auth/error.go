@@ -0,0 +1,175 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bbralion/CTFloodBot/pkg/services"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorKindCodes maps a services.ErrorKind to the gRPC status code the server
+// error interceptors report it as; the client error interceptors use its inverse
+// to recover the original kind.
+var errorKindCodes = map[services.ErrorKind]codes.Code{
+	services.ErrorKindNotFound:          codes.NotFound,
+	services.ErrorKindPermissionDenied:  codes.PermissionDenied,
+	services.ErrorKindInvalidArgument:   codes.InvalidArgument,
+	services.ErrorKindUnavailable:       codes.Unavailable,
+	services.ErrorKindInternal:          codes.Internal,
+	services.ErrorKindDeadlineExceeded:  codes.DeadlineExceeded,
+	services.ErrorKindAlreadyExists:     codes.AlreadyExists,
+	services.ErrorKindResourceExhausted: codes.ResourceExhausted,
+}
+
+var codeErrorKinds = func() map[codes.Code]services.ErrorKind {
+	m := make(map[codes.Code]services.ErrorKind, len(errorKindCodes))
+	for kind, code := range errorKindCodes {
+		m[code] = kind
+	}
+	return m
+}()
+
+// ErrorInterceptorOption configures the server error interceptors.
+type ErrorInterceptorOption func(*errorInterceptorConfig)
+
+type errorInterceptorConfig struct {
+	verbose bool
+}
+
+// WithVerboseErrors attaches a *services.ServiceError's Info as gRPC DebugInfo
+// details. Off by default, since Info may contain data (like an internal bot URL)
+// that's only safe to hand to trusted callers.
+func WithVerboseErrors() ErrorInterceptorOption {
+	return func(c *errorInterceptorConfig) { c.verbose = true }
+}
+
+func newErrorInterceptorConfig(opts []ErrorInterceptorOption) *errorInterceptorConfig {
+	cfg := &errorInterceptorConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// toStatus translates err into a gRPC status: a *services.ServiceError is mapped
+// via errorKindCodes using its safe Message, optionally carrying its Info as
+// DebugInfo; any other error becomes codes.Unknown.
+func toStatus(err error, cfg *errorInterceptorConfig) error {
+	var svcErr *services.ServiceError
+	if !errors.As(err, &svcErr) {
+		return status.Error(codes.Unknown, err.Error())
+	}
+
+	code, ok := errorKindCodes[svcErr.Kind]
+	if !ok {
+		code = codes.Internal
+	}
+
+	st := status.New(code, svcErr.Message)
+	if cfg.verbose && svcErr.Info != "" {
+		if withDetails, detailsErr := st.WithDetails(&errdetails.DebugInfo{Detail: svcErr.Info}); detailsErr == nil {
+			st = withDetails
+		}
+	}
+	return st.Err()
+}
+
+// fromStatus reverses toStatus: a status produced by it is translated back into a
+// *services.ServiceError, so callers can use errors.As and ServiceError.ZapFields()
+// exactly as if the error had originated locally. Errors that don't carry a
+// recognized status code, or aren't gRPC statuses at all, are returned unchanged.
+func fromStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	kind, ok := codeErrorKinds[st.Code()]
+	if !ok {
+		return err
+	}
+
+	var info string
+	for _, d := range st.Details() {
+		if dbg, ok := d.(*errdetails.DebugInfo); ok {
+			info = dbg.Detail
+			break
+		}
+	}
+	return &services.ServiceError{Wrapped: err, Info: info, Message: st.Message(), Kind: kind}
+}
+
+// UnaryServerErrorInterceptor translates handler errors into gRPC statuses via
+// toStatus, so a services.ServiceError's ErrorKind consistently becomes the same
+// status code everywhere it's returned.
+func UnaryServerErrorInterceptor(opts ...ErrorInterceptorOption) grpc.UnaryServerInterceptor {
+	cfg := newErrorInterceptorConfig(opts)
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, toStatus(err, cfg)
+		}
+		return resp, nil
+	}
+}
+
+// StreamServerErrorInterceptor is UnaryServerErrorInterceptor's streaming counterpart.
+func StreamServerErrorInterceptor(opts ...ErrorInterceptorOption) grpc.StreamServerInterceptor {
+	cfg := newErrorInterceptorConfig(opts)
+	return func(
+		srv interface{},
+		stream grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if err := handler(srv, stream); err != nil {
+			return toStatus(err, cfg)
+		}
+		return nil
+	}
+}
+
+// UnaryClientErrorInterceptor reverses UnaryServerErrorInterceptor's mapping via
+// fromStatus, so callers of the gRPC proxy get a typed *services.ServiceError back.
+func UnaryClientErrorInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		return fromStatus(invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
+
+// StreamClientErrorInterceptor is UnaryClientErrorInterceptor's streaming counterpart.
+func StreamClientErrorInterceptor() grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, fromStatus(err)
+		}
+		return stream, nil
+	}
+}
@@ -3,6 +3,9 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+
+	"github.com/bbralion/CTFloodBot/pkg/service"
+	"github.com/go-logr/logr"
 	telegramapi "github.com/go-telegram-bot-api/telegram-bot-api"
 	"github.com/kbats183/CTFloodBot/pkg/core"
 	"go.uber.org/zap"
@@ -10,14 +13,33 @@ import (
 
 type AnswerChan chan<- core.HandlerAnswer
 
+// SimpleHandler is a Service which receives updates and publishes answers over redis.
+// Start/Stop/Wait follow pkg/service's lifecycle contract instead of the previous
+// fire-and-forget Run(), so a supervisor can shut it down alongside other handlers.
 type SimpleHandler struct {
+	service.BaseService
 	Handler func(logger *zap.Logger, update *telegramapi.Update, answerChan AnswerChan)
 	Logger  *zap.Logger
 	Config  HandlerConfig
 }
 
-func (h *SimpleHandler) Run() {
-	ctx := context.Background()
+// Start begins receiving updates over redis. The handler keeps running until ctx is
+// canceled or Stop is called.
+func (h *SimpleHandler) Start(ctx context.Context) error {
+	if h.BaseService.Logger == (logr.Logger{}) {
+		h.BaseService.Logger = logr.Discard()
+	}
+	if err := h.BaseService.Start(ctx); err != nil {
+		return err
+	}
+
+	go h.run()
+	return nil
+}
+
+func (h *SimpleHandler) run() {
+	ctx := h.BaseService.Context()
+	defer h.BaseService.Finish(nil)
 
 	redisClient := core.GetRedisClientByConfig(h.Config.Redis)
 
@@ -31,14 +53,25 @@ func (h *SimpleHandler) Run() {
 	h.Logger.Info("Handler is ready to start")
 
 	subscriber := redisClient.Subscribe(ctx, core.RedisUpdateChanel)
-	for message := range subscriber.Channel() {
-		var update telegramapi.Update
-		err := json.Unmarshal([]byte(message.Payload), &update)
-		if err != nil {
-			h.Logger.Fatal("Failed to unmarshal received update", zap.Error(err), zap.String("message", message.Payload))
-		}
+	defer subscriber.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-subscriber.Channel():
+			if !ok {
+				return
+			}
 
-		go h.processUpdate(&update, publish)
+			var update telegramapi.Update
+			if err := json.Unmarshal([]byte(message.Payload), &update); err != nil {
+				h.Logger.Error("Failed to unmarshal received update", zap.Error(err), zap.String("message", message.Payload))
+				continue
+			}
+
+			go h.processUpdate(&update, publish)
+		}
 	}
 }
 
@@ -0,0 +1,239 @@
+// Package observability provides a chain of unary and stream gRPC interceptors
+// (OTel tracing, Prometheus metrics, panic recovery) shared by the mux proxy's
+// server (proxy.GRPC.setupGRPC) and the handler-side registrar
+// (services.NewGRPCRegistrar), so both sides of the multiplexer channel report
+// into the same tracing backend and metrics registry.
+package observability
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+// Observability bundles the tracing and metrics used by the mux gRPC interceptor
+// chain. The zero value is not usable; construct one with New.
+type Observability struct {
+	tracerProvider trace.TracerProvider
+	propagator     propagation.TextMapPropagator
+
+	requestsTotal         *prometheus.CounterVec
+	durationSeconds       *prometheus.HistogramVec
+	streamActive          *prometheus.GaugeVec
+	updatesForwardedTotal *prometheus.CounterVec
+	registrarRetriesTotal *prometheus.CounterVec
+}
+
+// Option configures an Observability instance created by New.
+type Option func(*Observability)
+
+// WithRegisterer registers the Observability's Prometheus metrics with reg instead
+// of the default registry, so tests can inject a throwaway prometheus.Registry.
+func WithRegisterer(reg prometheus.Registerer) Option {
+	return func(o *Observability) { o.register(reg) }
+}
+
+// WithTracerProvider uses tp instead of the global OTel TracerProvider, so tests
+// can inject an in-memory exporter.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(o *Observability) { o.tracerProvider = tp }
+}
+
+// New builds an Observability using the given options, defaulting to the global
+// OTel TracerProvider and the default Prometheus registerer.
+func New(opts ...Option) *Observability {
+	o := &Observability{
+		tracerProvider: otel.GetTracerProvider(),
+		propagator:     propagation.TraceContext{},
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.requestsTotal == nil {
+		o.register(prometheus.DefaultRegisterer)
+	}
+	return o
+}
+
+func (o *Observability) register(reg prometheus.Registerer) {
+	o.requestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "mux_rpc_requests_total",
+		Help: "Total number of mux gRPC RPCs handled, by method and status code.",
+	}, []string{"method", "code"})
+	o.durationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "mux_rpc_duration_seconds",
+		Help: "Duration of mux gRPC RPCs in seconds, by method.",
+	}, []string{"method"})
+	o.streamActive = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mux_stream_active",
+		Help: "Number of currently active mux gRPC streams, by method.",
+	}, []string{"method"})
+	o.updatesForwardedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "mux_updates_forwarded_total",
+		Help: "Total number of updates forwarded to a registered handler, by client.",
+	}, []string{"client"})
+	o.registrarRetriesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "mux_registrar_retries_total",
+		Help: "Total number of times the registrar retried a RegisterHandler dial or stream, by op.",
+	}, []string{"op"})
+
+	reg.MustRegister(o.requestsTotal, o.durationSeconds, o.streamActive, o.updatesForwardedTotal, o.registrarRetriesTotal)
+}
+
+// ObserveUpdateForwarded records that an Update was forwarded to client over its
+// RegisterHandler stream.
+func (o *Observability) ObserveUpdateForwarded(client string) {
+	o.updatesForwardedTotal.WithLabelValues(client).Inc()
+}
+
+// MetricsHandler serves the default Prometheus registry (prometheus.DefaultGatherer)
+// in the standard text exposition format, suitable for mounting at /metrics on the
+// advertised HTTP endpoint. Metrics registered on any other registry are not served.
+func (o *Observability) MetricsHandler() http.Handler {
+	return promhttp.Handler()
+}
+
+// StartRegistrarSpan starts a client-side span for a single registrar connection
+// attempt, op being "dial" (opening the RegisterHandler stream) or "stream"
+// (reading from it), so reconnect churn is visible in traces alongside the server
+// side spans started by StreamServerInterceptor.
+func (o *Observability) StartRegistrarSpan(ctx context.Context, op string) (context.Context, trace.Span) {
+	ctx, span := o.tracer().Start(ctx, "registrar."+op, trace.WithSpanKind(trace.SpanKindClient))
+	return ctx, span
+}
+
+// ObserveRegistrarRetry records a registrar retry of op ("dial" or "stream") as a
+// Prometheus counter increment and, if span is non-nil, a span event carrying the
+// error that triggered the retry.
+func (o *Observability) ObserveRegistrarRetry(span trace.Span, op string, err error) {
+	o.registrarRetriesTotal.WithLabelValues(op).Inc()
+	if span != nil {
+		span.AddEvent("retry", trace.WithAttributes(attribute.String("error", err.Error())))
+	}
+}
+
+// EndRegistrarSpan ends span, recording err as its status if non-nil.
+func (o *Observability) EndRegistrarSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+	} else {
+		span.SetStatus(otelcodes.Ok, "")
+	}
+	span.End()
+}
+
+// tracer is named after the package providing the instrumentation, per OTel convention.
+func (o *Observability) tracer() trace.Tracer {
+	return o.tracerProvider.Tracer("github.com/bbralion/CTFloodBot/pkg/observability")
+}
+
+// extractTraceContext propagates an incoming traceparent (if any) from gRPC
+// metadata into ctx, so the resulting span is a child of the caller's trace
+// instead of a new root trace.
+func (o *Observability) extractTraceContext(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+
+	carrier := make(propagation.MapCarrier, len(md))
+	for k, v := range md {
+		if len(v) > 0 {
+			carrier[k] = v[0]
+		}
+	}
+	return o.propagator.Extract(ctx, carrier)
+}
+
+// loggerWithTraceID attaches the active span's trace id to logger as a "trace_id"
+// field, so traces and logs for the same RPC can be correlated.
+func loggerWithTraceID(ctx context.Context, logger logr.Logger) logr.Logger {
+	span := trace.SpanContextFromContext(ctx)
+	if !span.HasTraceID() {
+		return logger
+	}
+	return logger.WithValues("trace_id", span.TraceID().String())
+}
+
+// UnaryServerInterceptor returns a unary server interceptor adding tracing,
+// Prometheus metrics and panic recovery around next (typically
+// auth.NewGRPCServerInterceptor's interceptor).
+func (o *Observability) UnaryServerInterceptor(logger logr.Logger, next grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		ctx = o.extractTraceContext(ctx)
+		ctx, span := o.tracer().Start(ctx, info.FullMethod, trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		start := time.Now()
+		defer func() {
+			if r := recover(); r != nil {
+				err = grpcstatus.Errorf(grpccodes.Internal, "panic handling %s: %v", info.FullMethod, r)
+				loggerWithTraceID(ctx, logger).Error(fmt.Errorf("%v", r), "recovered from panic in gRPC handler", "method", info.FullMethod)
+			}
+			o.observeRPC(span, info.FullMethod, time.Since(start), err)
+		}()
+
+		return next(ctx, req, info, handler)
+	}
+}
+
+// StreamServerInterceptor returns a stream server interceptor adding tracing,
+// Prometheus metrics and panic recovery around next (typically
+// auth.NewGRPCServerInterceptor's interceptor).
+func (o *Observability) StreamServerInterceptor(logger logr.Logger, next grpc.StreamServerInterceptor) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		ctx := o.extractTraceContext(stream.Context())
+		ctx, span := o.tracer().Start(ctx, info.FullMethod, trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		o.streamActive.WithLabelValues(info.FullMethod).Inc()
+		defer o.streamActive.WithLabelValues(info.FullMethod).Dec()
+
+		start := time.Now()
+		defer func() {
+			if r := recover(); r != nil {
+				err = grpcstatus.Errorf(grpccodes.Internal, "panic handling %s: %v", info.FullMethod, r)
+				loggerWithTraceID(ctx, logger).Error(fmt.Errorf("%v", r), "recovered from panic in gRPC handler", "method", info.FullMethod)
+			}
+			o.observeRPC(span, info.FullMethod, time.Since(start), err)
+		}()
+
+		return next(srv, &tracedServerStream{ServerStream: stream, ctx: ctx}, info, handler)
+	}
+}
+
+func (o *Observability) observeRPC(span trace.Span, method string, duration time.Duration, err error) {
+	code := grpcstatus.Code(err)
+	o.requestsTotal.WithLabelValues(method, code.String()).Inc()
+	o.durationSeconds.WithLabelValues(method).Observe(duration.Seconds())
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+	} else {
+		span.SetStatus(otelcodes.Ok, "")
+	}
+	span.SetAttributes(attribute.String("rpc.method", method), attribute.String("rpc.grpc.status_code", code.String()))
+}
+
+type tracedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *tracedServerStream) Context() context.Context { return s.ctx }
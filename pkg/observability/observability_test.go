@@ -0,0 +1,89 @@
+package observability
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context { return s.ctx }
+
+func TestObservability_StreamServerInterceptor_CreatesSpan(t *testing.T) {
+	req := require.New(t)
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	o := New(WithRegisterer(prometheus.NewRegistry()), WithTracerProvider(tp))
+
+	interceptor := o.StreamServerInterceptor(logr.Discard(), func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, stream)
+	})
+
+	stream := &fakeServerStream{ctx: context.Background()}
+	info := &grpc.StreamServerInfo{FullMethod: "/mux.MultiplexerService/RegisterHandler"}
+
+	err := interceptor(nil, stream, info, func(srv interface{}, stream grpc.ServerStream) error {
+		return nil
+	})
+	req.NoError(err)
+	req.NoError(tp.ForceFlush(context.Background()))
+
+	spans := exporter.GetSpans()
+	req.Len(spans, 1)
+	req.Equal(info.FullMethod, spans[0].Name)
+}
+
+func TestObservability_ObserveUpdateForwarded(t *testing.T) {
+	req := require.New(t)
+
+	o := New(WithRegisterer(prometheus.NewRegistry()))
+
+	o.ObserveUpdateForwarded("client1")
+	o.ObserveUpdateForwarded("client1")
+	o.ObserveUpdateForwarded("client2")
+
+	req.Equal(float64(2), testutil.ToFloat64(o.updatesForwardedTotal.WithLabelValues("client1")))
+	req.Equal(float64(1), testutil.ToFloat64(o.updatesForwardedTotal.WithLabelValues("client2")))
+}
+
+func TestObservability_extractTraceContext(t *testing.T) {
+	req := require.New(t)
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	o := New(WithRegisterer(prometheus.NewRegistry()), WithTracerProvider(tp))
+
+	rootCtx, root := o.tracer().Start(context.Background(), "root")
+	traceID := root.SpanContext().TraceID()
+	defer root.End()
+
+	carrier := propagation.MapCarrier{}
+	o.propagator.Inject(rootCtx, carrier)
+
+	md := metadata.MD{}
+	for k, v := range carrier {
+		md.Set(k, v)
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+
+	extracted := o.extractTraceContext(ctx)
+	_, span := o.tracer().Start(extracted, "child")
+	defer span.End()
+
+	req.Equal(traceID, span.SpanContext().TraceID())
+}
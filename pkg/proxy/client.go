@@ -20,6 +20,10 @@ type Client struct {
 	Handler Handler
 	// Matchers specify the matchers used to filter the requests which should be handled by this client
 	Matchers []string
+	// Envelope requests how updates are decoded on the registrar's wire. It is
+	// honored when registrar implements services.EnvelopeAwareRegistrar and
+	// ignored (as EnvelopeRaw) otherwise.
+	Envelope services.Envelope
 }
 
 // RegisterAndRun registers the client using the given registrar,
@@ -37,14 +41,20 @@ func (c *Client) RegisterAndRun(ctx context.Context, registrar services.Registra
 		}
 	}
 
-	updateCh, err := registrar.Register(ctx, matchers)
+	var conn *services.HandlerConn
+	var err error
+	if aware, ok := registrar.(services.EnvelopeAwareRegistrar); ok {
+		conn, err = aware.RegisterWithEnvelope(ctx, matchers, c.Envelope)
+	} else {
+		conn, err = registrar.Register(ctx, matchers)
+	}
 	if err != nil {
 		return fmt.Errorf("registering client: %w", err)
 	}
 
 	for {
 		select {
-		case update := <-updateCh:
+		case update := <-conn.Updates:
 			if update.Error != nil {
 				return fmt.Errorf("receiving updates: %w", err)
 			}
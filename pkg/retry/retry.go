@@ -1,8 +1,10 @@
 package retry
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"math/rand"
 	"time"
 
 	"google.golang.org/grpc/codes"
@@ -12,6 +14,9 @@ import (
 type (
 	DelayScheduler func() time.Duration
 	ErrTransformer func(error) error
+	// OnRetry, when non-nil, is called before sleeping ahead of every retry attempt
+	// (attempt is 1-indexed), so callers can emit structured logs/metrics per attempt.
+	OnRetry func(attempt int, delay time.Duration, err error)
 )
 
 type recoverError struct {
@@ -40,9 +45,17 @@ func Unrecoverable(err error) error {
 	return recoverError{err}
 }
 
-// Recover runs the function using a custom delay scheduler
-func Recover[T any](f func() (T, error), s DelayScheduler, et ...ErrTransformer) (T, error) {
-	for {
+// Recover runs the function using a custom delay scheduler. It returns early with
+// ctx.Err() as soon as ctx is canceled, whether that happens between attempts or
+// while waiting out the scheduled delay, instead of sleeping through cancellation.
+// onRetry, if non-nil, is invoked before every sleep with the attempt number (1-indexed).
+func Recover[T any](ctx context.Context, f func() (T, error), s DelayScheduler, onRetry OnRetry, et ...ErrTransformer) (T, error) {
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			var zero T
+			return zero, err
+		}
+
 		ret, err := f()
 		for _, t := range et {
 			err = t(err)
@@ -55,7 +68,19 @@ func Recover[T any](f func() (T, error), s DelayScheduler, et ...ErrTransformer)
 			return ret, re.wrapped
 		}
 
-		time.Sleep(s())
+		delay := s()
+		if onRetry != nil {
+			onRetry(attempt, delay, err)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			var zero T
+			return zero, ctx.Err()
+		case <-timer.C:
+		}
 	}
 }
 
@@ -66,27 +91,50 @@ const (
 )
 
 // Backoff runs the function using the backoff retry algorithm
-func Backoff[T any](f func() (T, error), et ...ErrTransformer) (T, error) {
+func Backoff[T any](ctx context.Context, f func() (T, error), onRetry OnRetry, et ...ErrTransformer) (T, error) {
 	delay, next := time.Duration(0), DefaultBackoffMinDelay
-	return Recover(f, func() time.Duration {
+	return Recover(ctx, f, func() time.Duration {
 		delay, next = next, next*DefaultBackoffFactor
 		if next > DefaultBackoffMaxDelay {
 			next = DefaultBackoffMaxDelay
 		}
 		return delay
-	}, et...)
+	}, onRetry, et...)
+}
+
+// BackoffWithJitter runs the function using decorrelated-jitter backoff
+// (next = min(cap, random_between(base, prev*3))), which avoids the thundering
+// herd of many clients reconnecting in lockstep after a shared outage.
+func BackoffWithJitter[T any](ctx context.Context, f func() (T, error), onRetry OnRetry, et ...ErrTransformer) (T, error) {
+	prev := DefaultBackoffMinDelay
+	return Recover(ctx, f, func() time.Duration {
+		upper := prev * 3
+		if upper > DefaultBackoffMaxDelay {
+			upper = DefaultBackoffMaxDelay
+		}
+		if upper <= DefaultBackoffMinDelay {
+			prev = DefaultBackoffMinDelay
+			return prev
+		}
+
+		delay := DefaultBackoffMinDelay + time.Duration(rand.Int63n(int64(upper-DefaultBackoffMinDelay)))
+		prev = delay
+		return delay
+	}, onRetry, et...)
 }
 
 const DefaultStaticDelay = time.Second
 
 // Static runs the function using a static retry delay
-func Static[T any](f func() (T, error), et ...ErrTransformer) (T, error) {
-	return Recover(f, func() time.Duration {
+func Static[T any](ctx context.Context, f func() (T, error), onRetry OnRetry, et ...ErrTransformer) (T, error) {
+	return Recover(ctx, f, func() time.Duration {
 		return DefaultStaticDelay
-	}, et...)
+	}, onRetry, et...)
 }
 
-// IsGRPCUnavailable is a helper for testing whether the error resembles a gRPC Unavailable status
+// IsGRPCUnavailable is a helper for testing whether the error resembles a gRPC Unavailable
+// status. Only an error that itself implements GRPCStatus() is recognized; status.FromError
+// does not unwrap, so a gRPC status wrapped via fmt.Errorf("%w") reports false.
 func IsGRPCUnavailable(err error) bool {
 	s, ok := status.FromError(err)
 	return ok && s.Code() == codes.Unavailable
@@ -1,8 +1,10 @@
 package retry
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -19,11 +21,18 @@ func assertNumCallsFunc(req *require.Assertions, n int, tmpErr, finalErr error)
 	}
 }
 
-func testStrategy(req *require.Assertions, n int, strategy func(func() (any, error), ...ErrTransformer) (any, error)) {
-	_, err := strategy(assertNumCallsFunc(req, n, errors.New("fake recoverable error"), nil))
+func testStrategy(req *require.Assertions, n int, strategy func(context.Context, func() (any, error), OnRetry, ...ErrTransformer) (any, error)) {
+	ctx := context.Background()
+
+	var attempts int
+	_, err := strategy(ctx, assertNumCallsFunc(req, n, errors.New("fake recoverable error"), nil), func(attempt int, delay time.Duration, err error) {
+		attempts++
+	})
 	req.NoError(err)
+	req.Equal(n-1, attempts, "onRetry should fire once per recoverable attempt")
+
 	e := errors.New("fake unrecoverable error")
-	_, err = strategy(assertNumCallsFunc(req, n, errors.New("fake recoverable error"), Unrecoverable(e)))
+	_, err = strategy(ctx, assertNumCallsFunc(req, n, errors.New("fake recoverable error"), Unrecoverable(e)), nil)
 	req.ErrorIs(e, err)
 }
 
@@ -33,5 +42,19 @@ func TestRetry(t *testing.T) {
 	for i := 1; i < 4; i++ {
 		testStrategy(req, i, Backoff[any])
 		testStrategy(req, i, Static[any])
+		testStrategy(req, i, BackoffWithJitter[any])
 	}
 }
+
+func TestRetry_ContextCanceled(t *testing.T) {
+	req := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := Static(ctx, func() (any, error) {
+		req.Fail("function should not be called once context is already canceled")
+		return nil, nil
+	}, nil)
+	req.ErrorIs(err, context.Canceled)
+}
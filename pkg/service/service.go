@@ -0,0 +1,139 @@
+// Package service provides a uniform lifecycle for long-running components
+// (handlers, streamers, registrars) so a supervisor can start many of them,
+// wait on whichever fails first, and shut them all down symmetrically.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/go-logr/logr"
+)
+
+// Service is a component with an explicit Start/Stop/Wait lifecycle.
+type Service interface {
+	// Start begins the service's work. ctx should span the lifetime of the service;
+	// canceling it is equivalent to calling Stop. Start must not block past initial setup.
+	Start(ctx context.Context) error
+	// Stop requests the service to shut down. It doesn't wait for shutdown to complete;
+	// use Wait for that. Safe to call multiple times and before Start returns.
+	Stop() error
+	// Wait returns a channel that is closed once the service has fully stopped.
+	Wait() <-chan struct{}
+}
+
+var (
+	// ErrAlreadyStarted is returned by Start when the service has already been started.
+	ErrAlreadyStarted = errors.New("service: already started")
+	// ErrAlreadyStopped is returned by Stop when the service has already been stopped.
+	ErrAlreadyStopped = errors.New("service: already stopped")
+	// ErrNotStarted is returned by Stop when the service was never started.
+	ErrNotStarted = errors.New("service: not started")
+)
+
+// BaseService implements the bookkeeping shared by every Service implementation:
+// the started/stopped state machine, logger tagging, and captured shutdown error.
+// Embed it and call Start/Stop/Wait from the wrapping type's own methods, e.g.:
+//
+//	func (s *myService) Start(ctx context.Context) error {
+//		if err := s.BaseService.Start(ctx); err != nil {
+//			return err
+//		}
+//		go s.run(s.BaseService.Context())
+//		return nil
+//	}
+type BaseService struct {
+	// Name is used to tag log messages and error wrapping; defaults to "service" if empty.
+	Name   string
+	Logger logr.Logger
+
+	mu      sync.Mutex
+	started bool
+	stopped bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+	done    chan struct{}
+	err     error
+}
+
+func (s *BaseService) name() string {
+	if s.Name == "" {
+		return "service"
+	}
+	return s.Name
+}
+
+// Start transitions the service into the started state, deriving a cancelable context
+// from ctx. Implementations should launch their work using BaseService.Context() so that
+// both ctx cancellation and an explicit Stop() tear the work down.
+func (s *BaseService) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return fmt.Errorf("%s: %w", s.name(), ErrAlreadyStarted)
+	}
+	s.started = true
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	s.done = make(chan struct{})
+
+	s.Logger.Info("starting service", "name", s.name())
+	return nil
+}
+
+// Context returns the context derived at Start time. Only valid after Start has been called.
+func (s *BaseService) Context() context.Context {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ctx
+}
+
+// Stop requests shutdown by canceling the context handed to the running work.
+// It does not itself close the Wait channel; the embedding service must call
+// Finish once its work has actually returned, typically via a deferred call.
+func (s *BaseService) Stop() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.started {
+		return fmt.Errorf("%s: %w", s.name(), ErrNotStarted)
+	}
+	if s.stopped {
+		return fmt.Errorf("%s: %w", s.name(), ErrAlreadyStopped)
+	}
+	s.stopped = true
+	s.cancel()
+	return nil
+}
+
+// Wait returns a channel closed once Finish has been called.
+func (s *BaseService) Wait() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.done
+}
+
+// Finish records the final error (if any) the service's work returned and
+// closes the Wait channel. Must be called exactly once, typically deferred
+// from the goroutine running the service's actual work.
+func (s *BaseService) Finish(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.err = err
+	if err != nil {
+		s.Logger.Error(err, "service stopped with error", "name", s.name())
+	} else {
+		s.Logger.Info("service stopped", "name", s.name())
+	}
+	close(s.done)
+}
+
+// Err returns the error Finish was called with, if any. Only meaningful after Wait closes.
+func (s *BaseService) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
@@ -0,0 +1,271 @@
+package services
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.uber.org/zap"
+)
+
+// DefaultAMQPExchange is the topic exchange NewAMQPProxy and NewAMQPMultiplexer
+// publish updates to and bind handler queues against, unless overridden.
+const DefaultAMQPExchange = "ctfloodbot.updates"
+
+// fanoutRoutingKey is bound by a handler whose matchers can't be reduced to a
+// single command (see matcherRoutingKey), so it receives every update published to
+// the exchange and matches them itself, the same way mapMux does in-process.
+const fanoutRoutingKey = "#"
+
+// anchoredCommandRe recognizes a matcher that only matches a single anchored bot
+// command, e.g. "^/start$" or "^/start" -- the same shape already singled out
+// elsewhere for special handling (see genproto.RegisterRequest.matchers' doc
+// comment on the server-side CommandMatch translation).
+var anchoredCommandRe = regexp.MustCompile(`^\^/(\w+)\$?$`)
+
+// commandRoutingKey returns the routing key used for command, hashed rather than
+// used literally so it stays within AMQP's allowed routing key character set
+// regardless of what command itself contains.
+func commandRoutingKey(command string) string {
+	sum := sha1.Sum([]byte(strings.ToLower(command)))
+	return "command." + hex.EncodeToString(sum[:])[:16]
+}
+
+// matcherRoutingKey returns the routing key a handler should bind if re is trivial
+// enough to reduce to a single command, and whether it was. ok == false means the
+// caller should bind fanoutRoutingKey and match client-side instead.
+func matcherRoutingKey(re *regexp.Regexp) (key string, ok bool) {
+	m := anchoredCommandRe.FindStringSubmatch(re.String())
+	if m == nil {
+		return "", false
+	}
+	return commandRoutingKey(m[1]), true
+}
+
+// updateRoutingKey returns the routing key NewAMQPMultiplexer publishes update
+// under: commandRoutingKey of its leading "/command", if it has one, or
+// "update.other" otherwise. Binding fanoutRoutingKey ("#") still matches either.
+func updateRoutingKey(update tgbotapi.Update) string {
+	if update.Message != nil && update.Message.IsCommand() {
+		return commandRoutingKey(update.Message.Command())
+	}
+	return "update.other"
+}
+
+// amqpProxy implements registration and update receival over RabbitMQ/AMQP 0.9.1:
+// RegisterHandler declares a durable, exclusive queue bound to Exchange with
+// routing keys derived from matchers, and consumes updates from it, acking each
+// only once it's been handed to the caller so a crashed handler's unacked updates
+// are requeued instead of lost.
+type amqpProxy struct {
+	conn     *amqp.Connection
+	l        *zap.Logger
+	exchange string
+	envelope Envelope
+}
+
+// AMQPConfig specifies the configuration of the AMQP/RabbitMQ-based proxy.
+// All fields are expected to be set unless specified otherwise.
+type AMQPConfig struct {
+	Logger *zap.Logger
+	Conn   *amqp.Connection
+	// Exchange is the topic exchange registrations bind against and updates are
+	// consumed from. DefaultAMQPExchange is used if empty.
+	Exchange string
+	// Envelope requests a payload format for updates delivered on the handler's
+	// queue. EnvelopeRaw (the zero value) is used if unset.
+	Envelope Envelope
+}
+
+// NewAMQPProxy constructs a new AMQP/RabbitMQ-based proxy.
+func NewAMQPProxy(config *AMQPConfig) (Proxy, error) {
+	if config.Logger == nil || config.Conn == nil {
+		return nil, errors.New("unable to create amqp proxy without required components")
+	}
+
+	exchange := config.Exchange
+	if exchange == "" {
+		exchange = DefaultAMQPExchange
+	}
+	return &amqpProxy{conn: config.Conn, l: config.Logger, exchange: exchange, envelope: config.Envelope}, nil
+}
+
+// RegisterHandler declares name's queue, binds it to the routing keys matchers
+// reduce to (falling back to fanoutRoutingKey for any matcher that doesn't), and
+// starts consuming it, decoding and re-verifying each delivery against matchers
+// before handing it to the caller, since a fanout-bound queue receives updates that
+// didn't actually match.
+func (p *amqpProxy) RegisterHandler(ctx context.Context, name string, matchers []regexp.Regexp) (tgbotapi.UpdatesChannel, error) {
+	ch, err := p.conn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("opening amqp channel: %w", err)
+	}
+
+	if err := ch.ExchangeDeclare(p.exchange, "topic", true, false, false, false, nil); err != nil {
+		return nil, fmt.Errorf("declaring exchange: %w", err)
+	}
+
+	queue, err := ch.QueueDeclare("handler."+name, true, true, true, false, nil)
+	if err != nil {
+		return nil, fmt.Errorf("declaring handler queue: %w", err)
+	}
+
+	routingKeys := routingKeysFor(matchers)
+	for _, key := range routingKeys {
+		if err := ch.QueueBind(queue.Name, key, p.exchange, false, nil); err != nil {
+			return nil, fmt.Errorf("binding handler queue to %q: %w", key, err)
+		}
+	}
+
+	deliveries, err := ch.Consume(queue.Name, "", false, true, false, false, nil)
+	if err != nil {
+		return nil, fmt.Errorf("consuming handler queue: %w", err)
+	}
+
+	updates := make(chan tgbotapi.Update)
+	go func() {
+		defer close(updates)
+		defer ch.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case delivery, ok := <-deliveries:
+				if !ok {
+					return
+				}
+
+				update, err := decodeEnvelopedUpdate(delivery.Body, p.envelope)
+				if err != nil {
+					p.l.Warn("failed to decode amqp update delivery", zap.Error(err), zap.String("handler", name))
+					delivery.Nack(false, false)
+					continue
+				}
+
+				if update.Message == nil || !matchesAny(matchers, update.Message.Text) {
+					delivery.Ack(false)
+					continue
+				}
+
+				select {
+				case updates <- update:
+					delivery.Ack(false)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return updates, nil
+}
+
+// routingKeysFor returns the deduplicated set of routing keys a queue should bind
+// for matchers: matcherRoutingKey's result for every matcher it applies to, or just
+// fanoutRoutingKey if any matcher doesn't reduce to one.
+func routingKeysFor(matchers []regexp.Regexp) []string {
+	keys := make([]string, 0, len(matchers))
+	seen := make(map[string]bool, len(matchers))
+	for i := range matchers {
+		key, ok := matcherRoutingKey(&matchers[i])
+		if !ok {
+			return []string{fanoutRoutingKey}
+		}
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return []string{fanoutRoutingKey}
+	}
+	return keys
+}
+
+// matchesAny reports whether any of matchers matches text, mirroring
+// MatcherGroup.MatchString's semantics for the plain []regexp.Regexp shape
+// RegisterHandler receives its matchers in.
+func matchesAny(matchers []regexp.Regexp, text string) bool {
+	for i := range matchers {
+		if matchers[i].MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *amqpProxy) InitBotAPI() (*tgbotapi.BotAPI, error) {
+	return nil, errors.New("amqp proxy has no http endpoint to construct a bot API around; use a separate Proxy for outbound calls")
+}
+
+// AMQPMultiplexer consumes a Telegram update source and republishes every update to
+// Exchange, so any number of amqpProxy-backed handlers (potentially on separate
+// multiplexer worker processes, for horizontal scaling) can consume it rather than
+// all routing through a single in-process mapMux.
+type AMQPMultiplexer struct {
+	conn     *amqp.Connection
+	l        *zap.Logger
+	exchange string
+	envelope string
+}
+
+// NewAMQPMultiplexer constructs an AMQPMultiplexer publishing onto exchange (or
+// DefaultAMQPExchange if empty) over conn.
+func NewAMQPMultiplexer(logger *zap.Logger, conn *amqp.Connection, exchange string) (*AMQPMultiplexer, error) {
+	if logger == nil || conn == nil {
+		return nil, errors.New("unable to create amqp multiplexer without required components")
+	}
+	if exchange == "" {
+		exchange = DefaultAMQPExchange
+	}
+	return &AMQPMultiplexer{conn: conn, l: logger, exchange: exchange}, nil
+}
+
+// Run declares the exchange and republishes every update from updates onto it,
+// keyed by updateRoutingKey, until ctx is canceled or updates is closed.
+func (m *AMQPMultiplexer) Run(ctx context.Context, updates tgbotapi.UpdatesChannel) error {
+	ch, err := m.conn.Channel()
+	if err != nil {
+		return fmt.Errorf("opening amqp channel: %w", err)
+	}
+	defer ch.Close()
+
+	if err := ch.ExchangeDeclare(m.exchange, "topic", true, false, false, false, nil); err != nil {
+		return fmt.Errorf("declaring exchange: %w", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case update, ok := <-updates:
+			if !ok {
+				return nil
+			}
+
+			body, err := json.Marshal(update)
+			if err != nil {
+				m.l.Warn("failed to marshal update for amqp publish", zap.Error(err))
+				continue
+			}
+
+			err = ch.PublishWithContext(ctx, m.exchange, updateRoutingKey(update), false, false, amqp.Publishing{
+				ContentType: "application/json",
+				Timestamp:   time.Now(),
+				Body:        body,
+			})
+			if err != nil {
+				m.l.Warn("failed to publish update to amqp exchange", zap.Error(err))
+			}
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package services
+
+import "strings"
+
+// Challenge is a single parsed WWW-Authenticate challenge, e.g. the Bearer
+// challenge in WWW-Authenticate: Bearer realm="https://auth.example.com/token",
+// service="registry",scope="repository:foo:pull" parses to
+// Challenge{Scheme: "bearer", Parameters: map[string]string{"realm": "...", ...}}.
+type Challenge struct {
+	Scheme     string
+	Parameters map[string]string
+}
+
+// parseAuthHeader parses every WWW-Authenticate header value into a Challenge, as
+// produced by a Docker Distribution-style token server. It assumes (as is typical
+// in practice) that a server offering more than one scheme sends one
+// WWW-Authenticate header per scheme, rather than packing multiple schemes into a
+// single comma-joined value -- doing otherwise is technically valid under RFC 7235
+// but unambiguous parsing of that form requires a full auth-scheme grammar, which
+// this deliberately doesn't implement.
+func parseAuthHeader(values []string) []Challenge {
+	challenges := make([]Challenge, 0, len(values))
+	for _, v := range values {
+		if c, ok := parseChallenge(v); ok {
+			challenges = append(challenges, c)
+		}
+	}
+	return challenges
+}
+
+// parseChallenge parses a single WWW-Authenticate header value into a Challenge.
+func parseChallenge(value string) (Challenge, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return Challenge{}, false
+	}
+
+	scheme, paramStr, ok := strings.Cut(value, " ")
+	if !ok {
+		return Challenge{Scheme: strings.ToLower(scheme)}, true
+	}
+
+	params := make(map[string]string)
+	for _, part := range splitChallengeParams(paramStr) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		params[strings.TrimSpace(k)] = strings.Trim(strings.TrimSpace(v), `"`)
+	}
+	return Challenge{Scheme: strings.ToLower(scheme), Parameters: params}, true
+}
+
+// splitChallengeParams splits a challenge's "key=value, key2=value2" parameter list
+// on commas that aren't inside a quoted value, since a scope value like
+// "repository:foo:pull,push" is itself comma-separated.
+func splitChallengeParams(s string) []string {
+	var parts []string
+	var inQuotes bool
+	start := 0
+	for i, r := range s {
+		switch r {
+		case '"':
+			inQuotes = !inQuotes
+		case ',':
+			if !inQuotes {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
@@ -1,10 +1,58 @@
 package services
 
-import "errors"
+import (
+	"errors"
+	"path"
+)
+
+// commandRightsMethod is the pseudo-HTTP-method under which Telegram update
+// commands are checked in Client.Rights (e.g. {"COMMAND": {"start", "help"}}),
+// mirroring how pkg/auth.grpcRightsMethod keys gRPC full method names.
+const commandRightsMethod = "COMMAND"
 
 // Client is an identification of a single client of a service
 type Client struct {
 	Name string
+	// Rights restricts the client to specific HTTP methods and path globs (as
+	// matched by path.Match), e.g. {"POST": {"/internal/register"}, "GET":
+	// {"/proxy/*"}}. A nil or empty Rights leaves the client unrestricted.
+	Rights map[string][]string
+	// Scopes are free-form capability tags (e.g. "updates.read", "admin")
+	// available for services to consult beyond the method-level Rights ACL.
+	Scopes []string
+	// RateLimit caps this client's requests per minute. Zero means unlimited.
+	RateLimit int
+}
+
+// Allowed reports whether Rights permits method against path. A client with no
+// Rights configured is unrestricted, so existing Authenticators that never set
+// Rights keep working unchanged.
+func (c Client) Allowed(method, reqPath string) bool {
+	if !c.Restricted() {
+		return true
+	}
+	for _, pattern := range c.Rights[method] {
+		if ok, err := path.Match(pattern, reqPath); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Restricted reports whether Rights limits this client in any way. Callers that
+// need to deny-by-default on something Allowed itself can't express -- e.g.
+// rejecting a matcher shape no permission check can evaluate -- should only do so
+// for a Restricted client, consistent with Allowed's own unrestricted escape hatch.
+func (c Client) Restricted() bool {
+	return len(c.Rights) > 0
+}
+
+// AllowsCommand reports whether Rights permits this client to receive the given
+// Telegram command, named the same way models.CommandMatcher.Name is (e.g. "start",
+// without the leading slash). It's Allowed specialized to commandRightsMethod, for
+// filtering which update commands are proxied to each client.
+func (c Client) AllowsCommand(command string) bool {
+	return c.Allowed(commandRightsMethod, command)
 }
 
 var ErrInvalidToken = errors.New("invalid authentication token provided")
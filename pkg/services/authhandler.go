@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// AuthHandler satisfies a single WWW-Authenticate challenge scheme, as dispatched
+// by internalHTTPTransport on a 401 response. Implementations are registered with
+// WithAuthHandler, keyed by their own Scheme().
+type AuthHandler interface {
+	// Scheme is the auth scheme this handler satisfies, e.g. "bearer" or "basic"
+	// (matched case-insensitively against the challenge).
+	Scheme() string
+	// AuthorizeRequest sets whatever headers req needs to satisfy a challenge
+	// carrying the given parameters (e.g. Bearer's realm/service/scope), before the
+	// request is retried.
+	AuthorizeRequest(req *http.Request, params map[string]string) error
+}
+
+// BasicAuthHandler satisfies a "Basic" challenge using a fixed username/password.
+type BasicAuthHandler struct {
+	Username string
+	Password string
+}
+
+func (h *BasicAuthHandler) Scheme() string { return "basic" }
+
+func (h *BasicAuthHandler) AuthorizeRequest(req *http.Request, _ map[string]string) error {
+	req.SetBasicAuth(h.Username, h.Password)
+	return nil
+}
+
+// DefaultBearerTokenTTL is how long a token server's response is cached when it
+// doesn't specify its own expires_in.
+const DefaultBearerTokenTTL = 5 * time.Minute
+
+type bearerTokenCacheEntry struct {
+	token  string
+	expiry time.Time
+}
+
+// BearerAuthHandler satisfies a "Bearer" challenge by requesting a fresh token from
+// the challenge's realm using HTTP Basic client credentials (as in Docker
+// Distribution's token auth), caching the result keyed by (service, scope) until it
+// expires.
+type BearerAuthHandler struct {
+	// Username and Password authenticate this client against the token server, if set.
+	Username string
+	Password string
+	// Client is used to request tokens; http.DefaultClient is used if nil.
+	Client *http.Client
+
+	mu    sync.Mutex
+	cache map[string]bearerTokenCacheEntry
+}
+
+func (h *BearerAuthHandler) Scheme() string { return "bearer" }
+
+func (h *BearerAuthHandler) AuthorizeRequest(req *http.Request, params map[string]string) error {
+	token, err := h.token(req.Context(), params)
+	if err != nil {
+		return fmt.Errorf("obtaining bearer token: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+func (h *BearerAuthHandler) token(ctx context.Context, params map[string]string) (string, error) {
+	key := params["service"] + " " + params["scope"]
+
+	h.mu.Lock()
+	if h.cache == nil {
+		h.cache = make(map[string]bearerTokenCacheEntry)
+	}
+	if entry, ok := h.cache[key]; ok && time.Now().Before(entry.expiry) {
+		h.mu.Unlock()
+		return entry.token, nil
+	}
+	h.mu.Unlock()
+
+	realm := params["realm"]
+	if realm == "" {
+		return "", errors.New("services: bearer challenge missing realm")
+	}
+
+	reqURL, err := url.Parse(realm)
+	if err != nil {
+		return "", fmt.Errorf("parsing realm %q: %w", realm, err)
+	}
+	q := reqURL.Query()
+	if service := params["service"]; service != "" {
+		q.Set("service", service)
+	}
+	if scope := params["scope"]; scope != "" {
+		q.Set("scope", scope)
+	}
+	reqURL.RawQuery = q.Encode()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("building token request: %w", err)
+	}
+	if h.Username != "" {
+		httpReq.SetBasicAuth(h.Username, h.Password)
+	}
+
+	client := h.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("requesting token from %s: %w", realm, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token request to %s failed with status %d", realm, resp.StatusCode)
+	}
+
+	var tokenResp struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", fmt.Errorf("decoding token response: %w", err)
+	}
+
+	token := tokenResp.Token
+	if token == "" {
+		token = tokenResp.AccessToken
+	}
+	if token == "" {
+		return "", fmt.Errorf("token response from %s carried no token", realm)
+	}
+
+	ttl := time.Duration(tokenResp.ExpiresIn) * time.Second
+	if ttl <= 0 {
+		ttl = DefaultBearerTokenTTL
+	}
+
+	h.mu.Lock()
+	h.cache[key] = bearerTokenCacheEntry{token: token, expiry: time.Now().Add(ttl)}
+	h.mu.Unlock()
+
+	return token, nil
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bbralion/CTFloodBot/internal/genproto"
+	"golang.org/x/sync/singleflight"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultConfigTTL is how long a cached GetConfig response is served without
+// re-fetching, used unless overridden by WithConfigTTL.
+const DefaultConfigTTL = time.Minute * 5
+
+// DefaultConfigMaxStaleness is how long a cached GetConfig response may keep being
+// served in place of an Unavailable/DeadlineExceeded error, used unless overridden
+// by WithConfigMaxStaleness.
+const DefaultConfigMaxStaleness = time.Minute * 30
+
+type configEntry struct {
+	resp      *genproto.ConfigResponse
+	fetchedAt time.Time
+}
+
+// CachingClient wraps a genproto.MultiplexerServiceClient, serving GetConfig from an
+// in-memory cache instead of round-tripping to the proxy on every call. Every other
+// RPC, including RegisterHandler, is passed straight through to the wrapped client.
+type CachingClient struct {
+	genproto.MultiplexerServiceClient
+	target       string
+	ttl          time.Duration
+	maxStaleness time.Duration
+
+	mu    sync.Mutex
+	entry *configEntry
+	group singleflight.Group
+}
+
+// CachingClientOption configures a CachingClient created by NewCachingClient.
+type CachingClientOption func(*CachingClient)
+
+// WithConfigTTL overrides DefaultConfigTTL.
+func WithConfigTTL(ttl time.Duration) CachingClientOption {
+	return func(c *CachingClient) { c.ttl = ttl }
+}
+
+// WithConfigMaxStaleness overrides DefaultConfigMaxStaleness.
+func WithConfigMaxStaleness(d time.Duration) CachingClientOption {
+	return func(c *CachingClient) { c.maxStaleness = d }
+}
+
+// NewCachingClient wraps client with a GetConfig cache keyed by target (typically
+// the dial target/address, so distinct proxies don't share an entry).
+func NewCachingClient(client genproto.MultiplexerServiceClient, target string, opts ...CachingClientOption) *CachingClient {
+	c := &CachingClient{
+		MultiplexerServiceClient: client,
+		target:                   target,
+		ttl:                      DefaultConfigTTL,
+		maxStaleness:             DefaultConfigMaxStaleness,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// GetConfig serves req from the cache if it's within ttl of its last fetch.
+// Otherwise it fetches a fresh response, single-flighting concurrent callers onto
+// one RPC. If the fetch fails with Unavailable/DeadlineExceeded, the last known-good
+// response is served instead as long as it's within maxStaleness.
+func (c *CachingClient) GetConfig(ctx context.Context, req *genproto.ConfigRequest, opts ...grpc.CallOption) (*genproto.ConfigResponse, error) {
+	if entry := c.cachedEntry(); entry != nil && time.Since(entry.fetchedAt) < c.ttl {
+		return entry.resp, nil
+	}
+
+	v, err, _ := c.group.Do(c.target, func() (interface{}, error) {
+		resp, err := c.MultiplexerServiceClient.GetConfig(ctx, req, opts...)
+		if err != nil {
+			if entry := c.cachedEntry(); entry != nil && isStaleServable(err) && time.Since(entry.fetchedAt) < c.maxStaleness {
+				return entry.resp, nil
+			}
+			return nil, err
+		}
+
+		c.mu.Lock()
+		c.entry = &configEntry{resp: resp, fetchedAt: time.Now()}
+		c.mu.Unlock()
+		return resp, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*genproto.ConfigResponse), nil
+}
+
+// Refresh invalidates the cache and re-fetches GetConfig, for callers that know the
+// proxy's configuration has changed and don't want to wait out the TTL.
+func (c *CachingClient) Refresh(ctx context.Context) error {
+	c.mu.Lock()
+	c.entry = nil
+	c.mu.Unlock()
+
+	_, err := c.GetConfig(ctx, &genproto.ConfigRequest{})
+	return err
+}
+
+func (c *CachingClient) cachedEntry() *configEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entry
+}
+
+func isStaleServable(err error) bool {
+	s, ok := status.FromError(err)
+	return ok && (s.Code() == codes.Unavailable || s.Code() == codes.DeadlineExceeded)
+}
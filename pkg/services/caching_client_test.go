@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bbralion/CTFloodBot/internal/genproto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeMuxClient struct {
+	genproto.MultiplexerServiceClient
+	calls int32
+	err   error
+	resp  *genproto.ConfigResponse
+}
+
+func (c *fakeMuxClient) GetConfig(context.Context, *genproto.ConfigRequest, ...grpc.CallOption) (*genproto.ConfigResponse, error) {
+	atomic.AddInt32(&c.calls, 1)
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.resp, nil
+}
+
+func TestCachingClient_GetConfig_CachesWithinTTL(t *testing.T) {
+	req := require.New(t)
+
+	fake := &fakeMuxClient{resp: &genproto.ConfigResponse{Config: &genproto.Config{ProxyEndpoint: "a"}}}
+	c := NewCachingClient(fake, "target", WithConfigTTL(time.Hour))
+
+	for i := 0; i < 5; i++ {
+		resp, err := c.GetConfig(context.Background(), &genproto.ConfigRequest{})
+		req.NoError(err)
+		req.Equal(fake.resp, resp)
+	}
+	req.EqualValues(1, fake.calls)
+}
+
+func TestCachingClient_GetConfig_RefetchesAfterTTL(t *testing.T) {
+	req := require.New(t)
+
+	fake := &fakeMuxClient{resp: &genproto.ConfigResponse{Config: &genproto.Config{ProxyEndpoint: "a"}}}
+	c := NewCachingClient(fake, "target", WithConfigTTL(time.Millisecond))
+
+	_, err := c.GetConfig(context.Background(), &genproto.ConfigRequest{})
+	req.NoError(err)
+
+	time.Sleep(time.Millisecond * 5)
+
+	_, err = c.GetConfig(context.Background(), &genproto.ConfigRequest{})
+	req.NoError(err)
+	req.EqualValues(2, fake.calls)
+}
+
+func TestCachingClient_GetConfig_ServesStaleOnUnavailable(t *testing.T) {
+	req := require.New(t)
+
+	fake := &fakeMuxClient{resp: &genproto.ConfigResponse{Config: &genproto.Config{ProxyEndpoint: "a"}}}
+	c := NewCachingClient(fake, "target", WithConfigTTL(time.Millisecond), WithConfigMaxStaleness(time.Hour))
+
+	_, err := c.GetConfig(context.Background(), &genproto.ConfigRequest{})
+	req.NoError(err)
+
+	time.Sleep(time.Millisecond * 5)
+	fake.err = status.Error(codes.Unavailable, "down")
+
+	resp, err := c.GetConfig(context.Background(), &genproto.ConfigRequest{})
+	req.NoError(err)
+	req.Equal(fake.resp, resp)
+}
+
+func TestCachingClient_GetConfig_PropagatesErrorPastMaxStaleness(t *testing.T) {
+	req := require.New(t)
+
+	fake := &fakeMuxClient{resp: &genproto.ConfigResponse{Config: &genproto.Config{ProxyEndpoint: "a"}}}
+	c := NewCachingClient(fake, "target", WithConfigTTL(time.Millisecond), WithConfigMaxStaleness(time.Millisecond))
+
+	_, err := c.GetConfig(context.Background(), &genproto.ConfigRequest{})
+	req.NoError(err)
+
+	time.Sleep(time.Millisecond * 10)
+	fake.err = status.Error(codes.Unavailable, "down")
+
+	_, err = c.GetConfig(context.Background(), &genproto.ConfigRequest{})
+	req.Error(err)
+}
+
+func TestCachingClient_GetConfig_PropagatesNonRetriableError(t *testing.T) {
+	req := require.New(t)
+
+	fake := &fakeMuxClient{resp: &genproto.ConfigResponse{Config: &genproto.Config{ProxyEndpoint: "a"}}, err: errors.New("boom")}
+	c := NewCachingClient(fake, "target")
+
+	_, err := c.GetConfig(context.Background(), &genproto.ConfigRequest{})
+	req.Error(err)
+}
+
+func TestCachingClient_Refresh_Invalidates(t *testing.T) {
+	req := require.New(t)
+
+	fake := &fakeMuxClient{resp: &genproto.ConfigResponse{Config: &genproto.Config{ProxyEndpoint: "a"}}}
+	c := NewCachingClient(fake, "target", WithConfigTTL(time.Hour))
+
+	_, err := c.GetConfig(context.Background(), &genproto.ConfigRequest{})
+	req.NoError(err)
+	req.EqualValues(1, fake.calls)
+
+	req.NoError(c.Refresh(context.Background()))
+	req.EqualValues(2, fake.calls)
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/google/uuid"
+)
+
+// CloudEventType is the CloudEvents "type" attribute stamped on every envelope
+// produced by WrapCloudEvent.
+const CloudEventType = "com.github.bbralion.ctfloodbot.update.v1"
+
+// CloudEvent is a CloudEvents 1.0 structured-mode JSON envelope around a single
+// telegram update, produced by WrapCloudEvent for registrations requesting
+// EnvelopeCloudEvents. See https://github.com/cloudevents/spec/blob/v1.0.2/cloudevents/spec.md.
+type CloudEvent struct {
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	SpecVersion     string          `json:"specversion"`
+	Type            string          `json:"type"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	Data            json.RawMessage `json:"data"`
+}
+
+// WrapCloudEvent wraps update in a CloudEvent envelope, stamping source as its
+// "source" attribute (typically "https://t.me/<bot_username>" or a configured
+// value) and a fresh random "id".
+func WrapCloudEvent(update tgbotapi.Update, source string) (*CloudEvent, error) {
+	data, err := json.Marshal(update)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling update: %w", err)
+	}
+
+	return &CloudEvent{
+		ID:              uuid.NewString(),
+		Source:          source,
+		SpecVersion:     "1.0",
+		Type:            CloudEventType,
+		Time:            time.Now(),
+		DataContentType: "application/json",
+		Data:            data,
+	}, nil
+}
+
+// UnwrapCloudEvent extracts the telegram update carried in a CloudEvent envelope
+// previously produced by WrapCloudEvent.
+func UnwrapCloudEvent(raw []byte) (tgbotapi.Update, error) {
+	var event CloudEvent
+	if err := json.Unmarshal(raw, &event); err != nil {
+		return tgbotapi.Update{}, fmt.Errorf("unmarshaling cloudevent envelope: %w", err)
+	}
+
+	var update tgbotapi.Update
+	if err := json.Unmarshal(event.Data, &update); err != nil {
+		return tgbotapi.Update{}, fmt.Errorf("unmarshaling cloudevent data: %w", err)
+	}
+	return update, nil
+}
+
+// decodeEnvelopedUpdate decodes raw according to envelope, unwrapping a CloudEvent
+// first if it requested EnvelopeCloudEvents.
+func decodeEnvelopedUpdate(raw []byte, envelope Envelope) (tgbotapi.Update, error) {
+	if envelope == EnvelopeCloudEvents {
+		return UnwrapCloudEvent(raw)
+	}
+
+	var update tgbotapi.Update
+	if err := json.Unmarshal(raw, &update); err != nil {
+		return tgbotapi.Update{}, err
+	}
+	return update, nil
+}
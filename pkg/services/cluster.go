@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bbralion/CTFloodBot/pkg/retry"
+)
+
+// DefaultClusterRebalanceInterval is how often a Cluster reshuffles its endpoint
+// order, used unless overridden by WithClusterRebalanceInterval.
+const DefaultClusterRebalanceInterval = 5 * time.Minute
+
+// ClusterError aggregates the per-endpoint failures from a Cluster.Do call that
+// exhausted every endpoint without any of them succeeding.
+type ClusterError struct {
+	Errors map[string]error
+}
+
+func (e *ClusterError) Error() string {
+	parts := make([]string, 0, len(e.Errors))
+	for endpoint, err := range e.Errors {
+		parts = append(parts, fmt.Sprintf("%s: %s", endpoint, err))
+	}
+	sort.Strings(parts)
+	return fmt.Sprintf("all %d cluster endpoints failed (%s)", len(e.Errors), strings.Join(parts, "; "))
+}
+
+// Cluster is modeled on etcd's httpClusterClient: it tries an ordered list of
+// endpoints in turn, starting from the last endpoint known to have succeeded (the
+// "pinned" endpoint), and periodically reshuffles that order so a cluster that's
+// been pinned to one endpoint for a long time doesn't keep sending every fresh
+// attempt's first try to it forever. This lets a redisHTTPProxy or gRPCRegistrar
+// (via ClusteredClient) survive one multiplexer replica restarting or rolling out
+// without failing requests outright.
+type Cluster struct {
+	mu        sync.Mutex
+	endpoints []string
+	pinned    int
+
+	rebalanceInterval time.Duration
+	rebalanceOnce     sync.Once
+}
+
+// ClusterOption configures a Cluster created by NewCluster.
+type ClusterOption func(*Cluster)
+
+// WithClusterRebalanceInterval overrides DefaultClusterRebalanceInterval.
+func WithClusterRebalanceInterval(d time.Duration) ClusterOption {
+	return func(c *Cluster) { c.rebalanceInterval = d }
+}
+
+// NewCluster creates a Cluster over endpoints, which must be non-empty. endpoints
+// are tried in the given order until the first periodic rebalance.
+func NewCluster(endpoints []string, opts ...ClusterOption) (*Cluster, error) {
+	if len(endpoints) == 0 {
+		return nil, errors.New("services: cluster requires at least one endpoint")
+	}
+
+	c := &Cluster{
+		endpoints:         append([]string(nil), endpoints...),
+		rebalanceInterval: DefaultClusterRebalanceInterval,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// Do calls f with each endpoint in turn, starting from the pinned endpoint and
+// wrapping around, until f succeeds or every endpoint has been tried. The first
+// endpoint f succeeds against becomes pinned for subsequent Do calls. ctx being
+// canceled or timing out short-circuits immediately with that error instead of
+// trying the next endpoint, since another endpoint can't fix a canceled caller;
+// every other error is recorded and the next endpoint is tried. If every endpoint
+// fails, Do returns a *ClusterError aggregating them.
+func (c *Cluster) Do(ctx context.Context, f func(ctx context.Context, endpoint string) error) error {
+	c.rebalanceOnce.Do(func() { go c.rebalanceLoop() })
+
+	order := c.order()
+	errs := make(map[string]error, len(order))
+
+	for _, endpoint := range order {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		err := f(ctx, endpoint)
+		if err == nil {
+			c.pin(endpoint)
+			return nil
+		}
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || retry.IsGRPCCanceled(err) {
+			return err
+		}
+		errs[endpoint] = err
+	}
+
+	return &ClusterError{Errors: errs}
+}
+
+// order returns the endpoint list starting from the pinned endpoint, wrapping
+// around, without mutating c.endpoints.
+func (c *Cluster) order() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ordered := make([]string, len(c.endpoints))
+	for i := range ordered {
+		ordered[i] = c.endpoints[(c.pinned+i)%len(c.endpoints)]
+	}
+	return ordered
+}
+
+// Pinned returns the endpoint Do will try first right now.
+func (c *Cluster) Pinned() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.endpoints[c.pinned]
+}
+
+// pin remembers endpoint as the last-good endpoint, so the next Do call tries it first.
+func (c *Cluster) pin(endpoint string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, e := range c.endpoints {
+		if e == endpoint {
+			c.pinned = i
+			return
+		}
+	}
+}
+
+// rebalanceLoop periodically reshuffles the endpoint order and resets pinned to 0,
+// so load doesn't permanently concentrate on whichever endpoint happened to answer
+// first after a long-lived Cluster was created.
+func (c *Cluster) rebalanceLoop() {
+	ticker := time.NewTicker(c.rebalanceInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		c.mu.Lock()
+		rand.Shuffle(len(c.endpoints), func(i, j int) {
+			c.endpoints[i], c.endpoints[j] = c.endpoints[j], c.endpoints[i]
+		})
+		c.pinned = 0
+		c.mu.Unlock()
+	}
+}
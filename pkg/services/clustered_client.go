@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+
+	"github.com/bbralion/CTFloodBot/internal/genproto"
+	"google.golang.org/grpc"
+)
+
+// ClusteredClient wraps multiple genproto.MultiplexerServiceClient instances --
+// typically one dialed per multiplexer replica -- behind a single
+// genproto.MultiplexerServiceClient, using a Cluster to fail over RPCs between them.
+// It composes with NewGRPCRegistrar and NewCachingClient exactly like a single
+// client would.
+type ClusteredClient struct {
+	cluster *Cluster
+	clients map[string]genproto.MultiplexerServiceClient
+}
+
+// NewClusteredClient creates a ClusteredClient over clients, keyed by an identifier
+// for each endpoint (typically its dial target/address). clients must be non-empty.
+func NewClusteredClient(clients map[string]genproto.MultiplexerServiceClient, opts ...ClusterOption) (*ClusteredClient, error) {
+	endpoints := make([]string, 0, len(clients))
+	for endpoint := range clients {
+		endpoints = append(endpoints, endpoint)
+	}
+
+	cluster, err := NewCluster(endpoints, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ClusteredClient{cluster: cluster, clients: clients}, nil
+}
+
+// GetConfig tries GetConfig against each endpoint in turn, per Cluster.Do.
+func (c *ClusteredClient) GetConfig(ctx context.Context, req *genproto.ConfigRequest, opts ...grpc.CallOption) (*genproto.ConfigResponse, error) {
+	var resp *genproto.ConfigResponse
+	err := c.cluster.Do(ctx, func(ctx context.Context, endpoint string) error {
+		r, err := c.clients[endpoint].GetConfig(ctx, req, opts...)
+		if err != nil {
+			return err
+		}
+		resp = r
+		return nil
+	})
+	return resp, err
+}
+
+// RegisterHandler tries opening a RegisterHandler stream against each endpoint in
+// turn, per Cluster.Do. Once a stream is established, subsequent failures on it are
+// the caller's responsibility to handle (e.g. by calling RegisterHandler again) --
+// Cluster.Do only covers the dial itself, not the stream's lifetime.
+func (c *ClusteredClient) RegisterHandler(ctx context.Context, opts ...grpc.CallOption) (genproto.MultiplexerService_RegisterHandlerClient, error) {
+	var stream genproto.MultiplexerService_RegisterHandlerClient
+	err := c.cluster.Do(ctx, func(ctx context.Context, endpoint string) error {
+		s, err := c.clients[endpoint].RegisterHandler(ctx, opts...)
+		if err != nil {
+			return err
+		}
+		stream = s
+		return nil
+	})
+	return stream, err
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/bbralion/CTFloodBot/internal/genproto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+)
+
+// TLSOptions configures the transport security used for the multiplexer<->handler
+// gRPC channel. CertFile/KeyFile are only required when mTLS (client certificates)
+// is desired; CAFile is the bundle used to validate the multiplexer's server certificate.
+type TLSOptions struct {
+	CertFile string
+	KeyFile  string
+	CAFile   string
+	// ServerName overrides the name used for server certificate verification
+	ServerName string
+	// InsecureSkipVerify disables server certificate verification; only for local testing
+	InsecureSkipVerify bool
+}
+
+// transportCredentials builds client-side credentials.TransportCredentials from TLSOptions.
+func (o TLSOptions) transportCredentials() (credentials.TransportCredentials, error) {
+	cfg := &tls.Config{
+		ServerName:         o.ServerName,
+		InsecureSkipVerify: o.InsecureSkipVerify,
+	}
+
+	if o.CAFile != "" {
+		ca, err := os.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, errors.New("no certificates found in CA bundle")
+		}
+		cfg.RootCAs = pool
+	}
+
+	if o.CertFile != "" || o.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return credentials.NewTLS(cfg), nil
+}
+
+// TokenSource returns the bearer token to present on each RPC, allowing
+// implementations that rotate the token (e.g. re-minting a short-lived JWT)
+// instead of a single static value.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// StaticToken is a TokenSource that always returns the same token.
+type StaticToken string
+
+func (t StaticToken) Token(context.Context) (string, error) {
+	return string(t), nil
+}
+
+// perRPCCredentials attaches the token from a TokenSource to every outgoing RPC,
+// identifying the calling handler to the multiplexer.
+type perRPCCredentials struct {
+	source     TokenSource
+	requireTLS bool
+}
+
+func (c *perRPCCredentials) GetRequestMetadata(ctx context.Context, _ ...string) (map[string]string, error) {
+	token, err := c.source.Token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("obtaining handler auth token: %w", err)
+	}
+	return map[string]string{handlerTokenKey: token}, nil
+}
+
+func (c *perRPCCredentials) RequireTransportSecurity() bool {
+	return c.requireTLS
+}
+
+const handlerTokenKey = "x-handler-token"
+
+// DialOptions configures DialMultiplexer's transport security and caller identity.
+type DialOptions struct {
+	// TLS, if non-nil, enables transport security (optionally mTLS) for the dial.
+	// If nil, the connection is made over plaintext, which is only sensible for loopback.
+	TLS *TLSOptions
+	// Token, if non-nil, is attached to every RPC identifying the calling handler.
+	Token TokenSource
+}
+
+// DialMultiplexer dials a multiplexer's gRPC endpoint using the transport security and
+// per-RPC handler credentials described by opts, returning a ready-to-use client. This
+// is the counterpart to NewHandlerAuthInterceptor on the multiplexer side.
+func DialMultiplexer(ctx context.Context, addr string, opts DialOptions) (genproto.MultiplexerServiceClient, *grpc.ClientConn, error) {
+	var dialOpts []grpc.DialOption
+
+	if opts.TLS != nil {
+		creds, err := opts.TLS.transportCredentials()
+		if err != nil {
+			return nil, nil, fmt.Errorf("building TLS credentials: %w", err)
+		}
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(creds))
+	} else {
+		dialOpts = append(dialOpts, grpc.WithInsecure()) //nolint:staticcheck // explicit opt-in, documented above
+	}
+
+	if opts.Token != nil {
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(&perRPCCredentials{
+			source:     opts.Token,
+			requireTLS: opts.TLS != nil,
+		}))
+	}
+
+	conn, err := grpc.DialContext(ctx, addr, dialOpts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("dialing multiplexer at %q: %w", addr, err)
+	}
+	return genproto.NewMultiplexerServiceClient(conn), conn, nil
+}
+
+// HandlerIdentity identifies the handler process claiming a MatcherGroup over
+// the multiplexer<->handler channel, as validated by NewHandlerAuthInterceptor.
+type HandlerIdentity struct {
+	Name string
+}
+
+type handlerIdentityCtxKey struct{}
+
+// HandlerIdentityFromContext retrieves the HandlerIdentity injected by
+// NewHandlerAuthInterceptor, if any.
+func HandlerIdentityFromContext(ctx context.Context) (HandlerIdentity, bool) {
+	id, ok := ctx.Value(handlerIdentityCtxKey{}).(HandlerIdentity)
+	return id, ok
+}
+
+// HandlerTokenValidator validates a handler's per-RPC token and resolves its identity.
+type HandlerTokenValidator func(token string) (HandlerIdentity, error)
+
+// HandlerAuthInterceptor is the server-side counterpart of perRPCCredentials: it
+// validates the token attached by DialMultiplexer and injects the resolved
+// HandlerIdentity into the RPC's context, so the multiplexer can log and authorize
+// which handler is claiming which MatcherGroup.
+type HandlerAuthInterceptor struct {
+	validate HandlerTokenValidator
+}
+
+// NewHandlerAuthInterceptor returns an interceptor which authenticates handlers
+// using the given validator.
+func NewHandlerAuthInterceptor(validate HandlerTokenValidator) *HandlerAuthInterceptor {
+	return &HandlerAuthInterceptor{validate}
+}
+
+func (i *HandlerAuthInterceptor) authorize(ctx context.Context) (context.Context, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md[handlerTokenKey]) != 1 {
+		return ctx, fmt.Errorf("missing %s metadata", handlerTokenKey)
+	}
+
+	identity, err := i.validate(md[handlerTokenKey][0])
+	if err != nil {
+		return ctx, fmt.Errorf("invalid handler token: %w", err)
+	}
+	return context.WithValue(ctx, handlerIdentityCtxKey{}, identity), nil
+}
+
+// Unary returns a unary gRPC server interceptor authenticating the calling handler.
+func (i *HandlerAuthInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := i.authorize(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// wrappedServerStream lets us hand the handler a ServerStream whose Context()
+// carries the injected HandlerIdentity.
+type wrappedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *wrappedServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// Stream returns a stream gRPC server interceptor authenticating the calling handler.
+func (i *HandlerAuthInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := i.authorize(stream.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &wrappedServerStream{stream, ctx})
+	}
+}
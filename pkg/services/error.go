@@ -1,24 +1,88 @@
 package services
 
-// error is the shared wrapper to be used for errors returned by services
-type svcError struct {
+import "go.uber.org/zap"
+
+// ErrorKind classifies a ServiceError for translation to a stable transport status
+// code (see auth.UnaryServerErrorInterceptor), independently of its human-readable
+// Message.
+type ErrorKind int
+
+const (
+	ErrorKindUnknown ErrorKind = iota
+	ErrorKindNotFound
+	ErrorKindPermissionDenied
+	ErrorKindInvalidArgument
+	ErrorKindUnavailable
+	ErrorKindInternal
+	ErrorKindDeadlineExceeded
+	ErrorKindAlreadyExists
+	ErrorKindResourceExhausted
+)
+
+// ServiceError is the shared wrapper to be used for errors returned by services.
+// Message is safe to expose to callers as-is; Info and the wrapped error are not
+// (e.g. Info may quote a request that itself embeds the bot URL) and are only
+// meant for server-side logging, or as gRPC DebugInfo when explicitly opted into.
+type ServiceError struct {
 	Wrapped error
-	Prefix  string
+	Info    string
 	Message string
+	Kind    ErrorKind
 }
 
-func (e *svcError) Unwrap() error {
+func (e *ServiceError) Unwrap() error {
 	return e.Wrapped
 }
 
-func (e *svcError) Error() string {
+func (e *ServiceError) Error() string {
 	return e.Message
 }
 
-func wrap(e error, p, m string) *svcError {
-	return &svcError{
-		Wrapped: e,
-		Prefix:  p,
-		Message: m,
-	}
+// ZapFields returns this error's fields for structured logging.
+func (e *ServiceError) ZapFields() []zap.Field {
+	return []zap.Field{zap.Error(e.Unwrap()), zap.String("info", e.Info), zap.String("message", e.Message)}
+}
+
+func wrap(kind ErrorKind, e error, info, msg string) *ServiceError {
+	return &ServiceError{Wrapped: e, Info: info, Message: msg, Kind: kind}
+}
+
+// NotFound wraps e as a ServiceError reported to gRPC callers as codes.NotFound.
+func NotFound(e error, info, msg string) *ServiceError {
+	return wrap(ErrorKindNotFound, e, info, msg)
+}
+
+// PermissionDenied wraps e as a ServiceError reported to gRPC callers as codes.PermissionDenied.
+func PermissionDenied(e error, info, msg string) *ServiceError {
+	return wrap(ErrorKindPermissionDenied, e, info, msg)
+}
+
+// InvalidArgument wraps e as a ServiceError reported to gRPC callers as codes.InvalidArgument.
+func InvalidArgument(e error, info, msg string) *ServiceError {
+	return wrap(ErrorKindInvalidArgument, e, info, msg)
+}
+
+// Unavailable wraps e as a ServiceError reported to gRPC callers as codes.Unavailable.
+func Unavailable(e error, info, msg string) *ServiceError {
+	return wrap(ErrorKindUnavailable, e, info, msg)
+}
+
+// Internal wraps e as a ServiceError reported to gRPC callers as codes.Internal.
+func Internal(e error, info, msg string) *ServiceError {
+	return wrap(ErrorKindInternal, e, info, msg)
+}
+
+// DeadlineExceeded wraps e as a ServiceError reported to gRPC callers as codes.DeadlineExceeded.
+func DeadlineExceeded(e error, info, msg string) *ServiceError {
+	return wrap(ErrorKindDeadlineExceeded, e, info, msg)
+}
+
+// AlreadyExists wraps e as a ServiceError reported to gRPC callers as codes.AlreadyExists.
+func AlreadyExists(e error, info, msg string) *ServiceError {
+	return wrap(ErrorKindAlreadyExists, e, info, msg)
+}
+
+// ResourceExhausted wraps e as a ServiceError reported to gRPC callers as codes.ResourceExhausted.
+func ResourceExhausted(e error, info, msg string) *ServiceError {
+	return wrap(ErrorKindResourceExhausted, e, info, msg)
 }
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// JWTOption configures a jwtAuthenticator or JWTIssuer created by
+// NewJWTAuthenticator / NewJWTIssuer.
+type JWTOption func(*jwtConfig)
+
+type jwtConfig struct {
+	method     jwt.SigningMethod
+	hmacKey    []byte
+	rsaPrivate *rsa.PrivateKey
+	rsaPublic  *rsa.PublicKey
+	nameClaim  string
+	issuer     string
+	audience   string
+}
+
+// WithHS256Key configures HMAC-SHA256 signing/verification using the given shared secret.
+func WithHS256Key(key []byte) JWTOption {
+	return func(c *jwtConfig) {
+		c.method = jwt.SigningMethodHS256
+		c.hmacKey = key
+	}
+}
+
+// WithRS256Keys configures RSA-SHA256 signing/verification. Either key may be nil
+// depending on whether the resulting value only needs to issue or only validate tokens.
+func WithRS256Keys(private *rsa.PrivateKey, public *rsa.PublicKey) JWTOption {
+	return func(c *jwtConfig) {
+		c.method = jwt.SigningMethodRS256
+		c.rsaPrivate = private
+		c.rsaPublic = public
+	}
+}
+
+// WithNameClaim overrides the claim used to populate Client.Name; defaults to "sub".
+func WithNameClaim(claim string) JWTOption {
+	return func(c *jwtConfig) { c.nameClaim = claim }
+}
+
+// WithIssuer requires and sets the token's "iss" claim.
+func WithIssuer(issuer string) JWTOption {
+	return func(c *jwtConfig) { c.issuer = issuer }
+}
+
+// WithAudience requires and sets the token's "aud" claim.
+func WithAudience(audience string) JWTOption {
+	return func(c *jwtConfig) { c.audience = audience }
+}
+
+func newJWTConfig(opts []JWTOption) *jwtConfig {
+	c := &jwtConfig{nameClaim: "sub"}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *jwtConfig) signingKey() (interface{}, error) {
+	switch c.method {
+	case jwt.SigningMethodHS256:
+		if c.hmacKey == nil {
+			return nil, errors.New("services: HS256 signing requires WithHS256Key")
+		}
+		return c.hmacKey, nil
+	case jwt.SigningMethodRS256:
+		if c.rsaPrivate == nil {
+			return nil, errors.New("services: RS256 signing requires a private key via WithRS256Keys")
+		}
+		return c.rsaPrivate, nil
+	default:
+		return nil, errors.New("services: no signing method configured, use WithHS256Key or WithRS256Keys")
+	}
+}
+
+func (c *jwtConfig) verificationKey() (interface{}, error) {
+	switch c.method {
+	case jwt.SigningMethodHS256:
+		if c.hmacKey == nil {
+			return nil, errors.New("services: HS256 verification requires WithHS256Key")
+		}
+		return c.hmacKey, nil
+	case jwt.SigningMethodRS256:
+		if c.rsaPublic == nil {
+			return nil, errors.New("services: RS256 verification requires a public key via WithRS256Keys")
+		}
+		return c.rsaPublic, nil
+	default:
+		return nil, errors.New("services: no signing method configured, use WithHS256Key or WithRS256Keys")
+	}
+}
+
+// jwtAuthenticator validates signed JWTs and extracts a Client from a configurable claim.
+// The verification key is resolved once in NewJWTAuthenticator and cached on the
+// instance rather than re-parsed on every Authenticate call.
+type jwtAuthenticator struct {
+	cfg *jwtConfig
+	key interface{}
+}
+
+// NewJWTAuthenticator returns an Authenticator which validates signed JWTs instead of
+// matching raw bearer tokens against a static map, so short-lived tokens minted by
+// JWTIssuer can replace permanently-valid static credentials without touching callers
+// of auth.NewGRPCServerInterceptor.
+func NewJWTAuthenticator(opts ...JWTOption) (Authenticator, error) {
+	cfg := newJWTConfig(opts)
+	key, err := cfg.verificationKey()
+	if err != nil {
+		return nil, err
+	}
+	return &jwtAuthenticator{cfg: cfg, key: key}, nil
+}
+
+func (a *jwtAuthenticator) Authenticate(token string) (Client, error) {
+	key := a.key
+
+	// jwt.ParseWithClaims already validates exp/nbf via MapClaims.Valid; iss/aud have
+	// no ParserOption in this library version so they're checked explicitly below.
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return key, nil
+	}, jwt.WithValidMethods([]string{a.cfg.method.Alg()})); err != nil {
+		return Client{}, ErrInvalidToken
+	}
+
+	if a.cfg.issuer != "" && !claims.VerifyIssuer(a.cfg.issuer, true) {
+		return Client{}, ErrInvalidToken
+	}
+	if a.cfg.audience != "" && !claims.VerifyAudience(a.cfg.audience, true) {
+		return Client{}, ErrInvalidToken
+	}
+
+	name, ok := claims[a.cfg.nameClaim].(string)
+	if !ok || name == "" {
+		return Client{}, ErrInvalidToken
+	}
+
+	rights, err := decodeRightsClaim(claims["rights"])
+	if err != nil {
+		return Client{}, ErrInvalidToken
+	}
+	return Client{Name: name, Rights: rights}, nil
+}
+
+// decodeRightsClaim decodes the "rights" claim produced by JWTIssuer.Issue back into
+// a Client.Rights map. raw is nil when the claim was omitted (an unrestricted
+// client), which decodes to a nil map rather than an error.
+func decodeRightsClaim(raw interface{}) (map[string][]string, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	untyped, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("services: malformed rights claim")
+	}
+
+	rights := make(map[string][]string, len(untyped))
+	for method, paths := range untyped {
+		untypedPaths, ok := paths.([]interface{})
+		if !ok {
+			return nil, errors.New("services: malformed rights claim")
+		}
+		rightPaths := make([]string, len(untypedPaths))
+		for i, p := range untypedPaths {
+			path, ok := p.(string)
+			if !ok {
+				return nil, errors.New("services: malformed rights claim")
+			}
+			rightPaths[i] = path
+		}
+		rights[method] = rightPaths
+	}
+	return rights, nil
+}
+
+// JWTIssuer mints short-lived JWTs for use with an Authenticator returned by
+// NewJWTAuthenticator, as an alternative to distributing long-lived static tokens.
+type JWTIssuer struct {
+	cfg *jwtConfig
+	key interface{}
+}
+
+// NewJWTIssuer returns a JWTIssuer which signs tokens using the given options.
+func NewJWTIssuer(opts ...JWTOption) (*JWTIssuer, error) {
+	cfg := newJWTConfig(opts)
+	key, err := cfg.signingKey()
+	if err != nil {
+		return nil, err
+	}
+	return &JWTIssuer{cfg: cfg, key: key}, nil
+}
+
+// Issue mints a signed, ttl-limited JWT identifying client, suitable for presentation
+// as a bearer token to an Authenticator returned by NewJWTAuthenticator.
+func (i *JWTIssuer) Issue(client Client, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		i.cfg.nameClaim: client.Name,
+		"iat":           jwt.NewNumericDate(now),
+		"nbf":           jwt.NewNumericDate(now),
+		"exp":           jwt.NewNumericDate(now.Add(ttl)),
+	}
+	if i.cfg.issuer != "" {
+		claims["iss"] = i.cfg.issuer
+	}
+	if i.cfg.audience != "" {
+		claims["aud"] = i.cfg.audience
+	}
+	if len(client.Rights) > 0 {
+		claims["rights"] = client.Rights
+	}
+
+	token := jwt.NewWithClaims(i.cfg.method, claims)
+	signed, err := token.SignedString(i.key)
+	if err != nil {
+		return "", fmt.Errorf("signing token: %w", err)
+	}
+	return signed, nil
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWTAuthenticator_Authenticate(t *testing.T) {
+	req := require.New(t)
+
+	issuer, err := NewJWTIssuer(WithHS256Key([]byte("secret")), WithIssuer("multiplexer"), WithAudience("handlers"))
+	req.NoError(err)
+
+	authenticator, err := NewJWTAuthenticator(WithHS256Key([]byte("secret")), WithIssuer("multiplexer"), WithAudience("handlers"))
+	req.NoError(err)
+
+	token, err := issuer.Issue(Client{Name: "client1"}, time.Minute)
+	req.NoError(err)
+
+	client, err := authenticator.Authenticate(token)
+	req.NoError(err)
+	req.Equal(Client{Name: "client1"}, client)
+}
+
+func TestJWTAuthenticator_Authenticate_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(req *require.Assertions) string
+	}{
+		{
+			name: "garbage token",
+			setup: func(req *require.Assertions) string {
+				return "not.a.jwt"
+			},
+		},
+		{
+			name: "expired token",
+			setup: func(req *require.Assertions) string {
+				issuer, err := NewJWTIssuer(WithHS256Key([]byte("secret")))
+				req.NoError(err)
+				token, err := issuer.Issue(Client{Name: "client1"}, -time.Minute)
+				req.NoError(err)
+				return token
+			},
+		},
+		{
+			name: "wrong signing key",
+			setup: func(req *require.Assertions) string {
+				issuer, err := NewJWTIssuer(WithHS256Key([]byte("other-secret")))
+				req.NoError(err)
+				token, err := issuer.Issue(Client{Name: "client1"}, time.Minute)
+				req.NoError(err)
+				return token
+			},
+		},
+		{
+			name: "wrong audience",
+			setup: func(req *require.Assertions) string {
+				issuer, err := NewJWTIssuer(WithHS256Key([]byte("secret")), WithAudience("other-audience"))
+				req.NoError(err)
+				token, err := issuer.Issue(Client{Name: "client1"}, time.Minute)
+				req.NoError(err)
+				return token
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			req := require.New(t)
+
+			authenticator, err := NewJWTAuthenticator(WithHS256Key([]byte("secret")), WithAudience("handlers"))
+			req.NoError(err)
+
+			token := tt.setup(req)
+			_, err = authenticator.Authenticate(token)
+			req.ErrorIs(err, ErrInvalidToken)
+		})
+	}
+}
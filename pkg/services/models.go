@@ -5,6 +5,9 @@ import "time"
 type RegisterHandlerRequest struct {
 	Name     string   `json:"name"`
 	Matchers []string `json:"matchers"`
+	// Envelope negotiates the payload format for updates delivered on the
+	// returned Channel. EnvelopeRaw (the zero value) is assumed if omitted.
+	Envelope Envelope `json:"envelope,omitempty"`
 }
 
 type RegisterHandlerResponse struct {
@@ -13,3 +16,15 @@ type RegisterHandlerResponse struct {
 	// Deadline is expected to be at least 30 seconds from now
 	Deadline time.Time `json:"deadline"`
 }
+
+// Envelope selects how updates are serialized onto a registration's Channel.
+type Envelope int
+
+const (
+	// EnvelopeRaw delivers the telegram update JSON as-is, with no wrapping.
+	EnvelopeRaw Envelope = iota
+	// EnvelopeCloudEvents wraps each update in a CloudEvent, so it can be piped
+	// into event-driven infrastructure (Kafka, NATS, Knative, ...) without
+	// re-serializing it.
+	EnvelopeCloudEvents
+)
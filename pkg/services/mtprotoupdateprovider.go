@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-logr/logr"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/gotd/td/session"
+	"github.com/gotd/td/telegram"
+	"github.com/gotd/td/tg"
+)
+
+// MTProtoUpdateProvider is an UpdateProvider that receives updates directly over
+// MTProto via github.com/gotd/td, instead of pollingUpdateProvider's Bot API long
+// polling. Messages are translated into the same tgbotapi.Update shape the other
+// providers produce, so MatcherGroup and handler code keeps working unchanged.
+type MTProtoUpdateProvider struct {
+	Logger logr.Logger
+	// AppID and AppHash identify the application with Telegram; see https://my.telegram.org.
+	AppID   int
+	AppHash string
+	// BotToken authenticates as a bot, the same way as for the Bot API providers.
+	BotToken string
+	// SessionStorage persists the MTProto session across restarts.
+	SessionStorage session.Storage
+	// Buffer sizes the channel returned by Updates; DefaultUpdatesBuffer is used if zero.
+	Buffer int
+
+	initOnce sync.Once
+	ch       chan tgbotapi.Update
+	nextID   int64
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+func (p *MTProtoUpdateProvider) init() {
+	p.initOnce.Do(func() {
+		buf := p.Buffer
+		if buf == 0 {
+			buf = DefaultUpdatesBuffer
+		}
+		p.ch = make(chan tgbotapi.Update, buf)
+	})
+}
+
+// Updates starts the MTProto client in the background and returns the channel it
+// forwards translated updates to. The client (and the channel) is shut down once
+// ctx is canceled.
+func (p *MTProtoUpdateProvider) Updates(ctx context.Context) tgbotapi.UpdatesChannel {
+	p.init()
+	go p.run(ctx)
+	return p.ch
+}
+
+func (p *MTProtoUpdateProvider) run(ctx context.Context) {
+	defer func() {
+		p.mu.Lock()
+		p.closed = true
+		close(p.ch)
+		p.mu.Unlock()
+	}()
+
+	dispatcher := tg.NewUpdateDispatcher()
+	dispatcher.OnNewMessage(func(ctx context.Context, e tg.Entities, u *tg.UpdateNewMessage) error {
+		return p.forward(u.Message)
+	})
+	dispatcher.OnNewChannelMessage(func(ctx context.Context, e tg.Entities, u *tg.UpdateNewChannelMessage) error {
+		return p.forward(u.Message)
+	})
+
+	client := telegram.NewClient(p.AppID, p.AppHash, telegram.Options{
+		SessionStorage: p.SessionStorage,
+		UpdateHandler:  dispatcher,
+	})
+
+	err := client.Run(ctx, func(ctx context.Context) error {
+		if _, err := client.Auth().Bot(ctx, p.BotToken); err != nil {
+			return err
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if err != nil && !errors.Is(err, context.Canceled) {
+		p.Logger.Error(err, "mtproto client stopped")
+	}
+}
+
+// forward translates msg into a tgbotapi.Update and pushes it onto the channel
+// returned by Updates, dropping anything that isn't a regular user message (e.g.
+// service messages) since existing handlers don't expect those either.
+func (p *MTProtoUpdateProvider) forward(msg tg.MessageClass) error {
+	m, ok := msg.(*tg.Message)
+	if !ok {
+		return nil
+	}
+
+	update := tgbotapi.Update{
+		UpdateID: int(atomic.AddInt64(&p.nextID, 1)),
+		Message: &tgbotapi.Message{
+			MessageID: m.ID,
+			Date:      m.Date,
+			Text:      m.Message,
+			Chat:      chatFromPeer(m.PeerID),
+			From:      userFromPeer(m.FromID),
+		},
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return nil
+	}
+
+	select {
+	case p.ch <- update:
+	default:
+		p.Logger.Info("dropping mtproto update, channel full")
+	}
+	return nil
+}
+
+// chatFromPeer maps a tg.PeerClass to the tgbotapi.Chat shape existing handlers
+// expect, approximating Bot API chat types from the MTProto peer kind.
+func chatFromPeer(peer tg.PeerClass) *tgbotapi.Chat {
+	switch p := peer.(type) {
+	case *tg.PeerUser:
+		return &tgbotapi.Chat{ID: p.UserID, Type: "private"}
+	case *tg.PeerChat:
+		return &tgbotapi.Chat{ID: p.ChatID, Type: "group"}
+	case *tg.PeerChannel:
+		return &tgbotapi.Chat{ID: p.ChannelID, Type: "channel"}
+	default:
+		return &tgbotapi.Chat{}
+	}
+}
+
+// userFromPeer maps a tg.PeerClass sender to a tgbotapi.User, or nil for
+// channel posts and other senderless messages, matching Bot API's Message.From.
+func userFromPeer(peer tg.PeerClass) *tgbotapi.User {
+	p, ok := peer.(*tg.PeerUser)
+	if !ok {
+		return nil
+	}
+	return &tgbotapi.User{ID: int(p.UserID)}
+}
+
+// NewMTProtoUpdateProvider creates an MTProtoUpdateProvider authenticating as a
+// bot with botToken.
+func NewMTProtoUpdateProvider(logger logr.Logger, appID int, appHash, botToken string, storage session.Storage) *MTProtoUpdateProvider {
+	return &MTProtoUpdateProvider{
+		Logger:         logger,
+		AppID:          appID,
+		AppHash:        appHash,
+		BotToken:       botToken,
+		SessionStorage: storage,
+	}
+}
@@ -2,82 +2,209 @@ package services
 
 import (
 	"context"
+	"errors"
+	"sort"
 	"sync"
 	"sync/atomic"
 
+	"github.com/bbralion/CTFloodBot/pkg/models"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
 )
 
+// ErrNoMatchers is returned by Multiplexer.Register when called with an empty
+// MatcherGroup, since a handler that can never match anything is almost
+// certainly a caller mistake rather than an intentional no-op registration.
+var ErrNoMatchers = errors.New("cannot register with zero matchers")
+
 // Multiplexer allows multiplexing various update handlers based on matchers
 type Multiplexer interface {
 	// Register registers a new handler which will receive updates until the context is canceled.
 	// Safe for concurrent use, so matchers can be registered from anywhere.
-	Register(ctx context.Context, matchers MatcherGroup) (UpdateChan, error)
+	Register(ctx context.Context, matchers models.MatcherGroup, opts ...RegisterOption) (models.UpdateChan, error)
 	// Serve multiplexes the update across the registered handlers.
 	// Isn't safe for concurrent use, so all calls to Serve must be from a single goroutine.
 	Serve(update tgbotapi.Update)
 }
 
+// DeliveryMode controls how Serve routes a matching update among the other
+// handlers whose matchers also accept it. The default, DeliveryBroadcast, is
+// mapMux's original fan-out-to-everyone behavior.
+type DeliveryMode int
+
+const (
+	// DeliveryBroadcast delivers a matching update to this handler regardless of
+	// whether any other handler also matched it.
+	DeliveryBroadcast DeliveryMode = iota
+	// DeliveryExclusive delivers a matching update only to the single
+	// highest-priority DeliveryExclusive handler that matches it; other
+	// DeliveryExclusive handlers matching the same update are skipped. Handlers
+	// with equal Priority are tried in registration order.
+	DeliveryExclusive
+	// DeliveryFallback delivers a matching update only if no DeliveryBroadcast or
+	// DeliveryExclusive handler matched it, so a catch-all matcher like "^.*$" can
+	// be registered without shadowing more specific handlers.
+	DeliveryFallback
+)
+
+// RegisterOption configures optional per-handler behavior on Register.
+type RegisterOption func(*muxHandler)
+
+// WithPriority sets the priority Serve orders this handler by when resolving
+// DeliveryExclusive and DeliveryFallback: handlers are tried highest priority
+// first, and equal priorities are tried in registration order. The default
+// priority is 0.
+func WithPriority(priority int) RegisterOption {
+	return func(h *muxHandler) { h.priority = priority }
+}
+
+// WithDeliveryMode sets the DeliveryMode this handler is registered under.
+// DeliveryBroadcast is the default.
+func WithDeliveryMode(mode DeliveryMode) RegisterOption {
+	return func(h *muxHandler) { h.mode = mode }
+}
+
 type (
 	muxKey     uint64
 	muxHandler struct {
 		ctx      context.Context
-		matchers MatcherGroup
+		matchers models.MatcherGroup
 		channel  chan tgbotapi.Update
+		priority int
+		mode     DeliveryMode
 	}
 )
 
+// orderedHandler pairs a muxHandler with its store key so mapMux.ordered can be
+// searched and spliced without a second lookup into store.
+type orderedHandler struct {
+	key muxKey
+	h   *muxHandler
+}
+
 // mapMux is a default implementation of Multiplexer
 type mapMux struct {
 	curKey    muxKey
 	store     sync.Map
 	bufferLen int
+
+	// mu guards ordered, a priority-descending (registration-order-ascending on
+	// ties) snapshot of store kept in sync with it, so Serve can resolve
+	// DeliveryExclusive/DeliveryFallback without having to sort on every call.
+	mu      sync.Mutex
+	ordered []*orderedHandler
 }
 
-func (m *mapMux) Register(ctx context.Context, matchers MatcherGroup) (UpdateChan, error) {
+func (m *mapMux) Register(ctx context.Context, matchers models.MatcherGroup, opts ...RegisterOption) (models.UpdateChan, error) {
 	if len(matchers) < 1 {
 		return nil, ErrNoMatchers
 	}
 
-	key := muxKey(atomic.AddUint64((*uint64)(&m.curKey), 1))
-	h := &muxHandler{ctx, matchers, make(chan tgbotapi.Update, m.bufferLen)}
+	h := &muxHandler{ctx: ctx, matchers: matchers, channel: make(chan tgbotapi.Update, m.bufferLen)}
+	for _, opt := range opts {
+		opt(h)
+	}
 
+	key := muxKey(atomic.AddUint64((*uint64)(&m.curKey), 1))
 	m.store.Store(key, h)
+	m.insert(key, h)
 	return h.channel, nil
 }
 
+// insert adds (key, h) to ordered, keeping it sorted by descending priority with
+// ties broken by registration order (i.e. appended after any existing entries of
+// equal priority).
+func (m *mapMux) insert(key muxKey, h *muxHandler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i := sort.Search(len(m.ordered), func(i int) bool { return m.ordered[i].h.priority < h.priority })
+	m.ordered = append(m.ordered, nil)
+	copy(m.ordered[i+1:], m.ordered[i:])
+	m.ordered[i] = &orderedHandler{key, h}
+}
+
+// unorder removes key from ordered, if present.
+func (m *mapMux) unorder(key muxKey) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, oh := range m.ordered {
+		if oh.key == key {
+			m.ordered = append(m.ordered[:i], m.ordered[i+1:]...)
+			return
+		}
+	}
+}
+
 func (m *mapMux) delete(key muxKey, h *muxHandler) {
 	m.store.Delete(key)
+	m.unorder(key)
 	close(h.channel)
 }
 
+// send delivers update to h, unregistering and closing it instead if its context
+// is already done -- either found dead beforehand or lost the race with the send.
+func (m *mapMux) send(key muxKey, h *muxHandler, update tgbotapi.Update) {
+	select {
+	case <-h.ctx.Done():
+		m.delete(key, h)
+	case h.channel <- update:
+	}
+}
+
 func (m *mapMux) Serve(update tgbotapi.Update) {
 	// Currently only messages are supported
 	if update.Message == nil {
 		return
 	}
 
-	m.store.Range(func(key, value any) bool {
-		mkey, mvalue := key.(muxKey), value.(*muxHandler)
+	m.mu.Lock()
+	ordered := make([]*orderedHandler, len(m.ordered))
+	copy(ordered, m.ordered)
+	m.mu.Unlock()
+
+	var fallback []*orderedHandler
+	exclusiveDelivered, delivered := false, false
 
-		// Fail-fast if the handler is already dead
+	for _, oh := range ordered {
+		key, h := oh.key, oh.h
+
+		// Fail-fast if the handler is already dead. A dead DeliveryExclusive
+		// handler is never considered "the winner", so the next matching handler
+		// still gets a chance at the update.
 		select {
-		case <-mvalue.ctx.Done():
-			m.delete(mkey, mvalue)
-			return true
+		case <-h.ctx.Done():
+			m.delete(key, h)
+			continue
 		default:
 		}
 
-		// Match and try to send if needed
-		if mvalue.matchers.MatchString(update.Message.Text) {
-			select {
-			case <-mvalue.ctx.Done():
-				m.delete(mkey, mvalue)
-			case mvalue.channel <- update:
+		if !h.matchers.MatchString(update.Message.Text) {
+			continue
+		}
+
+		switch h.mode {
+		case DeliveryFallback:
+			fallback = append(fallback, oh)
+		case DeliveryExclusive:
+			if exclusiveDelivered {
+				continue
 			}
+			exclusiveDelivered = true
+			delivered = true
+			m.send(key, h, update)
+		default:
+			delivered = true
+			m.send(key, h, update)
 		}
-		return true
-	})
+	}
+
+	if delivered {
+		return
+	}
+	for _, oh := range fallback {
+		m.send(oh.key, oh.h, update)
+	}
 }
 
 // NewMultiplexer creates a new multiplexer with the
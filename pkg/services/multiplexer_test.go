@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/bbralion/CTFloodBot/pkg/models"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/stretchr/testify/require"
+)
+
+func matcher(pattern string) models.MatcherGroup {
+	return models.MatcherGroup{regexp.MustCompile(pattern)}
+}
+
+func update(text string) tgbotapi.Update {
+	return tgbotapi.Update{Message: &tgbotapi.Message{Text: text}}
+}
+
+// tryReceive returns the next buffered update on ch and whether there was one,
+// without blocking -- Serve delivers synchronously into a buffered channel, so a
+// missing update means Serve genuinely skipped this handler.
+func tryReceive(ch models.UpdateChan) (tgbotapi.Update, bool) {
+	select {
+	case u, ok := <-ch:
+		return u, ok
+	default:
+		return tgbotapi.Update{}, false
+	}
+}
+
+func TestMapMux_DeliveryExclusive_PriorityWins(t *testing.T) {
+	req := require.New(t)
+	mux := NewMultiplexer(1)
+
+	low, err := mux.Register(context.Background(), matcher("^/help$"), WithDeliveryMode(DeliveryExclusive), WithPriority(0))
+	req.NoError(err)
+	high, err := mux.Register(context.Background(), matcher("^/help$"), WithDeliveryMode(DeliveryExclusive), WithPriority(10))
+	req.NoError(err)
+
+	mux.Serve(update("/help"))
+
+	got, ok := tryReceive(high)
+	req.True(ok, "higher priority handler should have received the update")
+	req.Equal("/help", got.Message.Text)
+
+	_, ok = tryReceive(low)
+	req.False(ok, "lower priority handler should have been skipped")
+}
+
+func TestMapMux_DeliveryExclusive_Starvation(t *testing.T) {
+	req := require.New(t)
+	mux := NewMultiplexer(1)
+
+	low, err := mux.Register(context.Background(), matcher("^/help$"), WithDeliveryMode(DeliveryExclusive), WithPriority(0))
+	req.NoError(err)
+	high, err := mux.Register(context.Background(), matcher("^/help$"), WithDeliveryMode(DeliveryExclusive), WithPriority(10))
+	req.NoError(err)
+
+	for i := 0; i < 5; i++ {
+		mux.Serve(update("/help"))
+
+		_, ok := tryReceive(high)
+		req.True(ok, "higher priority handler should win every time")
+		_, ok = tryReceive(low)
+		req.False(ok, "lower priority handler should starve while the winner is alive")
+	}
+}
+
+func TestMapMux_DeliveryExclusive_PriorityTies(t *testing.T) {
+	req := require.New(t)
+	mux := NewMultiplexer(1)
+
+	first, err := mux.Register(context.Background(), matcher("^/help$"), WithDeliveryMode(DeliveryExclusive))
+	req.NoError(err)
+	second, err := mux.Register(context.Background(), matcher("^/help$"), WithDeliveryMode(DeliveryExclusive))
+	req.NoError(err)
+
+	mux.Serve(update("/help"))
+
+	_, ok := tryReceive(first)
+	req.True(ok, "the first registered handler should win a priority tie")
+	_, ok = tryReceive(second)
+	req.False(ok, "the later registered handler should be skipped on a priority tie")
+}
+
+func TestMapMux_DeliveryFallback(t *testing.T) {
+	req := require.New(t)
+	mux := NewMultiplexer(1)
+
+	specific, err := mux.Register(context.Background(), matcher("^/help$"), WithDeliveryMode(DeliveryExclusive))
+	req.NoError(err)
+	catchAll, err := mux.Register(context.Background(), matcher("^.*$"), WithDeliveryMode(DeliveryFallback))
+	req.NoError(err)
+
+	mux.Serve(update("/help"))
+	_, ok := tryReceive(specific)
+	req.True(ok, "the specific handler should receive its matching update")
+	_, ok = tryReceive(catchAll)
+	req.False(ok, "the fallback handler shouldn't fire alongside a specific match")
+
+	mux.Serve(update("/unknown"))
+	_, ok = tryReceive(specific)
+	req.False(ok, "the specific handler shouldn't receive an update it doesn't match")
+	got, ok := tryReceive(catchAll)
+	req.True(ok, "the fallback handler should fire when nothing else matched")
+	req.Equal("/unknown", got.Message.Text)
+}
+
+func TestMapMux_DeliveryExclusive_CleanupOnCanceledWinner(t *testing.T) {
+	req := require.New(t)
+	mux := NewMultiplexer(1)
+
+	deadCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	high, err := mux.Register(deadCtx, matcher("^/help$"), WithDeliveryMode(DeliveryExclusive), WithPriority(10))
+	req.NoError(err)
+	low, err := mux.Register(context.Background(), matcher("^/help$"), WithDeliveryMode(DeliveryExclusive), WithPriority(0))
+	req.NoError(err)
+
+	mux.Serve(update("/help"))
+
+	_, ok := <-high
+	req.False(ok, "the canceled winner's channel should have been closed instead of receiving")
+
+	got, ok := tryReceive(low)
+	req.True(ok, "the update should still reach the next handler once the winner is cleaned up")
+	req.Equal("/help", got.Message.Text)
+}
+
+func TestMapMux_Broadcast(t *testing.T) {
+	req := require.New(t)
+	mux := NewMultiplexer(1)
+
+	a, err := mux.Register(context.Background(), matcher("^/help$"))
+	req.NoError(err)
+	b, err := mux.Register(context.Background(), matcher("^/help$"))
+	req.NoError(err)
+
+	mux.Serve(update("/help"))
+
+	_, ok := tryReceive(a)
+	req.True(ok, "broadcast handlers should all receive a matching update")
+	_, ok = tryReceive(b)
+	req.True(ok, "broadcast handlers should all receive a matching update")
+}
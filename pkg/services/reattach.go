@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/bbralion/CTFloodBot/internal/genproto"
+	"github.com/go-logr/logr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// ReattachEnvVar is the environment variable a standalone handler process
+// reads to discover an already-running multiplexer to attach to, instead
+// of going through the usual supervised wiring.
+const ReattachEnvVar = "REATTACH_MULTIPLEXER"
+
+// ReattachConfig describes an already-running multiplexer a handler should
+// attach to. It is carried as a JSON blob inside ReattachEnvVar so that a
+// handler binary can be launched standalone (e.g. under delve or the VS
+// Code debugger) and still register against the real multiplexer.
+type ReattachConfig struct {
+	// Address is the gRPC address of the running multiplexer
+	Address string `json:"address"`
+	// Token is the optional per-RPC auth token to present, if any
+	Token string `json:"token,omitempty"`
+	// Matchers are the matchers the handler should register with
+	Matchers []string `json:"matchers"`
+}
+
+// parseReattachConfig reads and validates the ReattachConfig carried in ReattachEnvVar
+func parseReattachConfig() (*ReattachConfig, error) {
+	raw, ok := os.LookupEnv(ReattachEnvVar)
+	if !ok {
+		return nil, fmt.Errorf("%s is not set", ReattachEnvVar)
+	}
+
+	var cfg ReattachConfig
+	if err := json.Unmarshal([]byte(raw), &cfg); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", ReattachEnvVar, err)
+	}
+	if cfg.Address == "" || len(cfg.Matchers) < 1 {
+		return nil, fmt.Errorf("%s must specify an address and at least one matcher", ReattachEnvVar)
+	}
+	return &cfg, nil
+}
+
+// compiledMatchers compiles the matchers carried in the parsed ReattachConfig
+func (c *ReattachConfig) compiledMatchers() ([]*regexp.Regexp, error) {
+	matchers := make([]*regexp.Regexp, len(c.Matchers))
+	for i, m := range c.Matchers {
+		compiled, err := regexp.Compile(m)
+		if err != nil {
+			return nil, fmt.Errorf("compiling reattach matcher %q: %w", m, err)
+		}
+		matchers[i] = compiled
+	}
+	return matchers, nil
+}
+
+// tokenPerRPCCredentials attaches a static token to every RPC, mirroring auth.GRPCClientInterceptor
+// but kept local to avoid pkg/auth depending back on pkg/services for reattach wiring.
+type tokenPerRPCCredentials string
+
+func (t tokenPerRPCCredentials) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": string(t)}, nil
+}
+
+func (t tokenPerRPCCredentials) RequireTransportSecurity() bool {
+	return false
+}
+
+// NewReattachRegistrar parses ReattachEnvVar and dials the multiplexer it describes, returning a
+// Registrar already wired up and ready to call Register with the matchers carried in the env var.
+// It is meant for running a single handler binary outside of the multiplexer's supervision, e.g.
+// under a debugger, while keeping updates flowing from an already-running multiplexer.
+func NewReattachRegistrar(logger logr.Logger) (Registrar, []*regexp.Regexp, error) {
+	cfg, err := parseReattachConfig()
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading reattach config: %w", err)
+	}
+
+	matchers, err := cfg.compiledMatchers()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	if cfg.Token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(tokenPerRPCCredentials(cfg.Token)))
+	}
+
+	conn, err := grpc.Dial(cfg.Address, opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("dialing reattach address %q: %w", cfg.Address, err)
+	}
+
+	return NewGRPCRegistrar(logger, genproto.NewMultiplexerServiceClient(conn)), matchers, nil
+}
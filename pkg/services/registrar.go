@@ -2,66 +2,173 @@ package services
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/bbralion/CTFloodBot/internal/genproto"
 	"github.com/bbralion/CTFloodBot/internal/models"
+	"github.com/bbralion/CTFloodBot/pkg/observability"
 	"github.com/bbralion/CTFloodBot/pkg/retry"
+	"github.com/bbralion/CTFloodBot/pkg/service"
 	"github.com/go-logr/logr"
-	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Registrar allows registration of command handlers for subsequent receival of updates
 type Registrar interface {
 	// Register registers a new command handler with the given matchers.
 	// The context should span the lifetime of the registered handler and canceled when it dies.
-	Register(ctx context.Context, matchers models.MatcherGroup) (models.UpdateChan, error)
+	Register(ctx context.Context, matchers models.MatcherGroup) (*HandlerConn, error)
+}
+
+// EnvelopeAwareRegistrar is implemented by Registrars that can be asked to decode
+// updates delivered on the returned HandlerConn according to a given Envelope,
+// instead of always assuming EnvelopeRaw. Callers that care should type-assert for
+// it, as proxy.Client does, and fall back to plain Register otherwise.
+type EnvelopeAwareRegistrar interface {
+	Registrar
+	RegisterWithEnvelope(ctx context.Context, matchers models.MatcherGroup, envelope Envelope) (*HandlerConn, error)
+}
+
+// HandlerConn is the live connection returned by Registrar.Register. Besides the
+// inbound Updates channel, Send lets the handler push outbound Telegram Bot API
+// actions back through the proxy (sendMessage, answerCallbackQuery, ...) instead of
+// dialing the Bot API itself.
+type HandlerConn struct {
+	Updates models.UpdateChan
+
+	mu                sync.Mutex
+	stream            genproto.MultiplexerService_RegisterHandlerClient
+	nextCorrelationID uint64
+	pending           map[string]chan *genproto.ActionResult
+}
+
+// Send pushes action through the proxy and waits for its matching ActionResult, or
+// for ctx to be canceled first. action.CorrelationId is overwritten with an id
+// unique to this connection.
+func (c *HandlerConn) Send(ctx context.Context, action *genproto.Action) (*genproto.ActionResult, error) {
+	action.CorrelationId = strconv.FormatUint(atomic.AddUint64(&c.nextCorrelationID, 1), 10)
+
+	resultCh := make(chan *genproto.ActionResult, 1)
+	c.mu.Lock()
+	stream := c.stream
+	c.pending[action.CorrelationId] = resultCh
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, action.CorrelationId)
+		c.mu.Unlock()
+	}()
+
+	if stream == nil {
+		return nil, errors.New("handler is not currently connected to the proxy")
+	}
+	if err := stream.Send(&genproto.HandlerMessage{Payload: &genproto.HandlerMessage_Action{Action: action}}); err != nil {
+		return nil, fmt.Errorf("sending action: %w", err)
+	}
+
+	select {
+	case result := <-resultCh:
+		return result, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// deliverActionResult routes an ActionResult frame to the Send call awaiting it, if any.
+func (c *HandlerConn) deliverActionResult(result *genproto.ActionResult) {
+	c.mu.Lock()
+	resultCh, ok := c.pending[result.CorrelationId]
+	c.mu.Unlock()
+	if ok {
+		resultCh <- result
+	}
+}
+
+// setStream records the current reconnection's stream, so Send keeps working across
+// tryRegister's automatic reconnects.
+func (c *HandlerConn) setStream(stream genproto.MultiplexerService_RegisterHandlerClient) {
+	c.mu.Lock()
+	c.stream = stream
+	c.mu.Unlock()
 }
 
 // gRPCRegistrar is an implementation of Registrar using grpc with retries
 type gRPCRegistrar struct {
 	logger logr.Logger
 	client genproto.MultiplexerServiceClient
+	obs    *observability.Observability
 }
 
-func (r *gRPCRegistrar) tryRegister(ctx context.Context, request *genproto.RegisterRequest, updateCh chan models.PossibleUpdate) error {
-	stream, err := retry.Backoff(func() (genproto.MultiplexerService_RegisterHandlerClient, error) {
-		stream, err := r.client.RegisterHandler(ctx, request)
-		if err == nil {
-			return stream, nil
+func (r *gRPCRegistrar) tryRegister(ctx context.Context, request *genproto.RegisterRequest, conn *HandlerConn, updateCh chan models.PossibleUpdate, envelope Envelope) (err error) {
+	var span trace.Span
+	if r.obs != nil {
+		ctx, span = r.obs.StartRegistrarSpan(ctx, "stream")
+		defer func() { r.obs.EndRegistrarSpan(span, err) }()
+	}
+
+	stream, err := retry.Backoff(ctx, func() (genproto.MultiplexerService_RegisterHandlerClient, error) {
+		stream, err := r.client.RegisterHandler(ctx)
+		if err != nil {
+			if retry.IsGRPCUnavailable(err) {
+				r.logger.Error(err, "gRPC registrar retrying connection to server")
+				if r.obs != nil {
+					r.obs.ObserveRegistrarRetry(span, "dial", err)
+				}
+				return nil, retry.Recoverable()
+			}
+			return nil, retry.Unrecoverable(err)
 		}
-		if retry.IsGRPCUnavailable(err) {
-			r.logger.Error(err, "gRPC registrar retrying connection to server")
-			return nil, retry.Recoverable()
+		if err := stream.Send(&genproto.HandlerMessage{Payload: &genproto.HandlerMessage_Register{Register: request}}); err != nil {
+			return nil, retry.Unrecoverable(fmt.Errorf("sending registration: %w", err))
 		}
-		return nil, retry.Unrecoverable(err)
+		return stream, nil
+	}, func(attempt int, delay time.Duration, err error) {
+		r.logger.V(1).Info("retrying RegisterHandler dial", "attempt", attempt, "delay", delay, "error", err)
 	})
 	if err != nil {
 		return fmt.Errorf("registering handler: %w", err)
 	}
+	conn.setStream(stream)
 
 	for {
-		updatePB, err := stream.Recv()
+		msg, err := stream.Recv()
 		if err != nil {
-			return fmt.Errorf("receiving update: %w", err)
+			return fmt.Errorf("receiving message: %w", err)
 		}
 
-		var update tgbotapi.Update
-		if err := json.Unmarshal([]byte(updatePB.Json), &update); err != nil {
-			return fmt.Errorf("unmarshaling update json: %w", err)
-		}
+		switch payload := msg.Payload.(type) {
+		case *genproto.HandlerMessage_Update:
+			update, err := decodeEnvelopedUpdate(payload.Update.Json, envelope)
+			if err != nil {
+				return fmt.Errorf("unmarshaling update json: %w", err)
+			}
 
-		select {
-		case updateCh <- models.PossibleUpdate{Update: update}:
-		case <-ctx.Done():
-			return nil
+			select {
+			case updateCh <- models.PossibleUpdate{Update: update}:
+			case <-ctx.Done():
+				return nil
+			}
+		case *genproto.HandlerMessage_ActionResult:
+			conn.deliverActionResult(payload.ActionResult)
 		}
 	}
 }
 
-func (r *gRPCRegistrar) Register(ctx context.Context, matchers models.MatcherGroup) (models.UpdateChan, error) {
+func (r *gRPCRegistrar) Register(ctx context.Context, matchers models.MatcherGroup) (*HandlerConn, error) {
+	return r.RegisterWithEnvelope(ctx, matchers, EnvelopeRaw)
+}
+
+// RegisterWithEnvelope behaves like Register, except updates delivered on the
+// returned HandlerConn are decoded according to envelope. The proxy's wire format
+// for genproto.Update.Json isn't negotiated over the RPC itself (RegisterRequest
+// has no field for it), so the caller and whatever publishes updates on the other
+// end must already agree on it out of band.
+func (r *gRPCRegistrar) RegisterWithEnvelope(ctx context.Context, matchers models.MatcherGroup, envelope Envelope) (*HandlerConn, error) {
 	if len(matchers) < 1 {
 		return nil, errors.New("cannot register with zero matchers")
 	}
@@ -74,30 +181,71 @@ func (r *gRPCRegistrar) Register(ctx context.Context, matchers models.MatcherGro
 	}
 
 	updateCh := make(chan models.PossibleUpdate)
+	conn := &HandlerConn{
+		Updates: updateCh,
+		pending: make(map[string]chan *genproto.ActionResult),
+	}
+
+	// bs only tracks this single registration's own goroutine lifecycle (started as soon as
+	// Register is called, stopped when ctx is canceled), giving it the same uniform
+	// Start/Stop/Wait bookkeeping used by handlers and streamers rather than a bare goroutine.
+	bs := &service.BaseService{Name: "registrar", Logger: r.logger}
+	if err := bs.Start(ctx); err != nil {
+		return nil, fmt.Errorf("starting registration: %w", err)
+	}
+
 	go func() {
 		defer close(updateCh)
 
-		_, err := retry.Static(func() (any, error) {
-			err := r.tryRegister(ctx, request, updateCh)
+		_, registerErr := retry.Static(bs.Context(), func() (any, error) {
+			err := r.tryRegister(bs.Context(), request, conn, updateCh, envelope)
 			if uw := errors.Unwrap(err); uw == nil || retry.IsGRPCCanceled(uw) {
 				return nil, nil
 			} else if retry.IsGRPCUnavailable(uw) {
 				r.logger.Error(err, "gRPC registrar reconnecting stream")
+				if r.obs != nil {
+					r.obs.ObserveRegistrarRetry(nil, "stream", uw)
+				}
 				return nil, retry.Recoverable()
 			}
 			return nil, retry.Unrecoverable(err)
+		}, func(attempt int, delay time.Duration, err error) {
+			r.logger.V(1).Info("retrying stream reconnect", "attempt", attempt, "delay", delay, "error", err)
 		})
-		if err != nil {
-			updateCh <- models.PossibleUpdate{Error: err}
+		if registerErr != nil {
+			updateCh <- models.PossibleUpdate{Error: registerErr}
 		}
+		bs.Finish(registerErr)
 	}()
-	return updateCh, nil
+	return conn, nil
+}
+
+// RegistrarOption configures a Registrar created by NewGRPCRegistrar.
+type RegistrarOption func(*gRPCRegistrar)
+
+// WithRegistrarObservability reports the registrar's dial/stream retries to obs as
+// spans and counters, so reconnect churn on the handler side shows up alongside the
+// proxy's own server-side telemetry.
+func WithRegistrarObservability(obs *observability.Observability) RegistrarOption {
+	return func(r *gRPCRegistrar) { r.obs = obs }
+}
+
+// WithCachedConfig wraps the registrar's client in a CachingClient keyed by target
+// (typically the dial address), so GetConfig calls made through the registrar's
+// client are served from the cache. tryRegister itself only calls RegisterHandler,
+// which the cache passes through unchanged.
+func WithCachedConfig(target string, opts ...CachingClientOption) RegistrarOption {
+	return func(r *gRPCRegistrar) { r.client = NewCachingClient(r.client, target, opts...) }
 }
 
 // NewGRPCRegistrar creates a Registrar based on the gRPC API client with preconfigured retries
-func NewGRPCRegistrar(logger logr.Logger, client genproto.MultiplexerServiceClient) Registrar {
+func NewGRPCRegistrar(logger logr.Logger, client genproto.MultiplexerServiceClient, opts ...RegistrarOption) Registrar {
 	if logger == (logr.Logger{}) {
 		logger = logr.Discard()
 	}
-	return &gRPCRegistrar{logger.WithName("registrar"), client}
+	r := &gRPCRegistrar{logger: logger.WithName("registrar"), client: client}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
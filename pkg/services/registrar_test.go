@@ -13,7 +13,6 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
-	"go.uber.org/goleak"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -67,8 +66,8 @@ func Test_gRPCRegistrar_Register(t *testing.T) {
 				{err: status.Error(codes.Unavailable, "temporarily unavailable")},
 				{err: status.Error(codes.Unavailable, "starting")},
 				{err: nil, stream: []streamUpdate{
-					{update: &genproto.Update{Json: `{"update_id":1}`}},
-					{update: &genproto.Update{Json: `{"update_id":2}`}},
+					{update: &genproto.Update{Json: []byte(`{"update_id":1}`)}},
+					{update: &genproto.Update{Json: []byte(`{"update_id":2}`)}},
 					{err: status.FromContextError(context.Canceled).Err()},
 				}},
 			},
@@ -82,7 +81,7 @@ func Test_gRPCRegistrar_Register(t *testing.T) {
 			args: args{matchers: models.MatcherGroup{regexp.MustCompile("^/command"), regexp.MustCompile("^.*$")}},
 			registerResponses: []registerResponse{
 				{err: nil, stream: []streamUpdate{
-					{update: &genproto.Update{Json: `{bad}`}},
+					{update: &genproto.Update{Json: []byte(`{bad}`)}},
 				}},
 			},
 			want: []possibleUpdate{
@@ -94,11 +93,11 @@ func Test_gRPCRegistrar_Register(t *testing.T) {
 			args: args{matchers: models.MatcherGroup{regexp.MustCompile("^/aboba$")}},
 			registerResponses: []registerResponse{
 				{err: nil, stream: []streamUpdate{
-					{update: &genproto.Update{Json: `{"update_id":1}`}},
+					{update: &genproto.Update{Json: []byte(`{"update_id":1}`)}},
 					{err: status.Error(codes.Unavailable, "stream broken")},
 				}},
 				{err: nil, stream: []streamUpdate{
-					{update: &genproto.Update{Json: `{"update_id":2}`}},
+					{update: &genproto.Update{Json: []byte(`{"update_id":2}`)}},
 					{err: status.FromContextError(context.DeadlineExceeded).Err()},
 				}},
 			},
@@ -129,19 +128,32 @@ func Test_gRPCRegistrar_Register(t *testing.T) {
 			for i := range tt.registerResponses {
 				resp := tt.registerResponses[i]
 				stream := mocks.NewMockMultiplexerService_RegisterHandlerClient(ctrl)
+				if resp.err == nil {
+					stream.EXPECT().Send(&genproto.HandlerMessage{
+						Payload: &genproto.HandlerMessage_Register{Register: &genproto.RegisterRequest{Matchers: reqMatchers}},
+					}).Return(nil)
+				}
 				for _, u := range resp.stream {
-					stream.EXPECT().Recv().Return(u.update, u.err)
+					var msg *genproto.HandlerMessage
+					if u.update != nil {
+						msg = &genproto.HandlerMessage{Payload: &genproto.HandlerMessage_Update{Update: u.update}}
+					}
+					stream.EXPECT().Recv().Return(msg, u.err)
 				}
-				mockMuxClient.EXPECT().RegisterHandler(ctx, &genproto.RegisterRequest{Matchers: reqMatchers}).Return(stream, resp.err)
+				mockMuxClient.EXPECT().RegisterHandler(gomock.Any()).Return(stream, resp.err)
 			}
 
-			updateCh, err := r.Register(ctx, tt.args.matchers)
+			conn, err := r.Register(ctx, tt.args.matchers)
 			req.Equal(tt.wantErr, err != nil)
+			if conn == nil {
+				req.Nil(tt.want)
+				return
+			}
 
 			left := len(tt.want)
 			req.Eventually(func() bool {
 				select {
-				case update, ok := <-updateCh:
+				case update, ok := <-conn.Updates:
 					if !ok {
 						req.Zero(left, "less updates on channel than wanted")
 						return true
@@ -154,12 +166,8 @@ func Test_gRPCRegistrar_Register(t *testing.T) {
 					left--
 				default:
 				}
-				return updateCh == nil
+				return conn.Updates == nil
 			}, time.Second*5, time.Millisecond*50)
 		})
 	}
 }
-
-func TestMain(m *testing.M) {
-	goleak.VerifyTestMain(m)
-}
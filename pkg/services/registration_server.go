@@ -0,0 +1,269 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bbralion/CTFloodBot/pkg/models"
+	"github.com/go-logr/logr"
+)
+
+// DefaultRegistrationDeadline is the registration lifetime RegistrationServer
+// grants when Deadline is unset, and MinRegistrationDeadline is the shortest
+// lifetime it will ever grant, per RegisterHandlerResponse.Deadline's "at least
+// 30 seconds from now" doc comment.
+const (
+	DefaultRegistrationDeadline = 60 * time.Second
+	MinRegistrationDeadline     = 30 * time.Second
+)
+
+// registrationJanitorInterval is how often RegistrationServer sweeps for expired
+// registrations, separate from the request-handling path so an idle server still
+// reclaims dead registrations promptly.
+const registrationJanitorInterval = 5 * time.Second
+
+// registration is one live (client, name) registration's Multiplexer handle.
+type registration struct {
+	client   Client
+	name     string
+	channel  string
+	envelope Envelope
+	cancel   context.CancelFunc
+	deadline time.Time
+}
+
+// RegistrationServer implements the HTTP side of the RegisterHandlerRequest/
+// RegisterHandlerResponse contract: it authenticates the caller, registers its
+// matchers with Multiplexer, and hands back a Channel id good until Deadline,
+// renewable via ServeRenew before it lapses. Mount ServeRegister and ServeRenew at
+// POST /register and POST /register/renew respectively.
+type RegistrationServer struct {
+	Logger        logr.Logger
+	Authenticator Authenticator
+	Multiplexer   Multiplexer
+	// Deadline is how long a registration is valid before it must be renewed.
+	// DefaultRegistrationDeadline is used if zero, and it is never allowed to go
+	// below MinRegistrationDeadline.
+	Deadline time.Duration
+
+	initOnce    sync.Once
+	mu          sync.Mutex
+	byKey       map[string]*registration
+	reqCounter  int64
+	janitorOnce sync.Once
+}
+
+func (s *RegistrationServer) init() {
+	s.initOnce.Do(func() {
+		s.byKey = make(map[string]*registration)
+		if s.Deadline == 0 {
+			s.Deadline = DefaultRegistrationDeadline
+		}
+		if s.Deadline < MinRegistrationDeadline {
+			s.Deadline = MinRegistrationDeadline
+		}
+	})
+	s.janitorOnce.Do(func() { go s.janitor() })
+}
+
+// registrationKey returns the stable key identifying a (client, name) tuple's
+// registration. It doubles as the Channel id handed back to the caller, so it stays
+// consistent across renewals as RegisterHandlerResponse.Channel requires.
+func registrationKey(client Client, name string) string {
+	return client.Name + "/" + name
+}
+
+func (s *RegistrationServer) janitor() {
+	ticker := time.NewTicker(registrationJanitorInterval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		s.expire(now)
+	}
+}
+
+// expire unregisters and drops every registration whose deadline has passed.
+// Canceling a registration's context is what tells Multiplexer to unregister its
+// matchers, the same way a handler's own context cancellation does.
+func (s *RegistrationServer) expire(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, r := range s.byKey {
+		if now.Before(r.deadline) {
+			continue
+		}
+
+		r.cancel()
+		delete(s.byKey, key)
+		s.Logger.Info("registration expired", "client", r.client.Name, "name", r.name, "channel", r.channel)
+	}
+}
+
+// ServeRegister handles POST /register: it creates a registration for the
+// requested name and matchers, or, if one already exists for this (client, name),
+// renews it in place and returns its existing Channel unchanged.
+func (s *RegistrationServer) ServeRegister(w http.ResponseWriter, r *http.Request) {
+	s.init()
+	reqID := atomic.AddInt64(&s.reqCounter, 1)
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	client, ok := s.authenticate(w, r, reqID)
+	if !ok {
+		return
+	}
+
+	var req RegisterHandlerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.Logger.Error(err, "decoding register request", "request_id", reqID, "client", client.Name)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if req.Name == "" || len(req.Matchers) < 1 {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	resp, err := s.register(r.Context(), client, &req)
+	if err != nil {
+		s.Logger.Error(err, "registration failed", "request_id", reqID, "client", client.Name, "name", req.Name)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	s.writeJSON(w, http.StatusCreated, resp)
+}
+
+// ServeRenew handles POST /register/renew: it pushes out the deadline of an
+// already-existing (client, name) registration, returning its unchanged Channel.
+// It never creates a new registration; a caller whose registration has already
+// expired must go through ServeRegister again.
+func (s *RegistrationServer) ServeRenew(w http.ResponseWriter, r *http.Request) {
+	s.init()
+	reqID := atomic.AddInt64(&s.reqCounter, 1)
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	client, ok := s.authenticate(w, r, reqID)
+	if !ok {
+		return
+	}
+
+	var req RegisterHandlerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.Logger.Error(err, "decoding renew request", "request_id", reqID, "client", client.Name)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	resp, err := s.renew(client, req.Name)
+	if err != nil {
+		s.Logger.Info("renewal rejected, no such registration", "request_id", reqID, "client", client.Name, "name", req.Name)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	s.Logger.Info("registration renewed", "request_id", reqID, "client", client.Name, "name", req.Name, "channel", resp.Channel)
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *RegistrationServer) authenticate(w http.ResponseWriter, r *http.Request, reqID int64) (Client, bool) {
+	if s.Authenticator == nil {
+		s.Logger.Info("registration server has no authenticator configured", "request_id", reqID)
+		w.WriteHeader(http.StatusInternalServerError)
+		return Client{}, false
+	}
+
+	client, err := s.Authenticator.Authenticate(r.Header.Get("Authorization"))
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return Client{}, false
+	}
+	if !client.Allowed(r.Method, r.URL.Path) {
+		w.WriteHeader(http.StatusForbidden)
+		return Client{}, false
+	}
+	return client, true
+}
+
+// register creates or, for an already-registered (client, req.Name), renews a
+// registration, allocating its Channel via Multiplexer so the matchers actually
+// receive updates.
+func (s *RegistrationServer) register(ctx context.Context, client Client, req *RegisterHandlerRequest) (*RegisterHandlerResponse, error) {
+	matchers := make(models.MatcherGroup, len(req.Matchers))
+	for i, m := range req.Matchers {
+		compiled, err := regexp.Compile(m)
+		if err != nil {
+			return nil, fmt.Errorf("compiling matcher %q: %w", m, err)
+		}
+		matchers[i] = compiled
+	}
+
+	key := registrationKey(client, req.Name)
+	deadline := time.Now().Add(s.Deadline)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r, ok := s.byKey[key]; ok {
+		r.deadline = deadline
+		s.Logger.Info("registration renewed via register", "client", client.Name, "name", req.Name, "channel", r.channel, "envelope", r.envelope)
+		return &RegisterHandlerResponse{Channel: r.channel, Deadline: deadline}, nil
+	}
+
+	regCtx, cancel := context.WithCancel(context.Background())
+	if _, err := s.Multiplexer.Register(regCtx, matchers); err != nil {
+		cancel()
+		return nil, fmt.Errorf("registering with multiplexer: %w", err)
+	}
+
+	r := &registration{client: client, name: req.Name, channel: key, envelope: req.Envelope, cancel: cancel, deadline: deadline}
+	s.byKey[key] = r
+	s.Logger.Info("registration created", "client", client.Name, "name", req.Name, "channel", r.channel, "envelope", r.envelope)
+
+	return &RegisterHandlerResponse{Channel: r.channel, Deadline: deadline}, nil
+}
+
+// renew pushes out the deadline of the existing registration for (client, name),
+// returning an error if there isn't one.
+func (s *RegistrationServer) renew(client Client, name string) (*RegisterHandlerResponse, error) {
+	key := registrationKey(client, name)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.byKey[key]
+	if !ok {
+		return nil, fmt.Errorf("no active registration named %q for client %q", name, client.Name)
+	}
+
+	r.deadline = time.Now().Add(s.Deadline)
+	return &RegisterHandlerResponse{Channel: r.channel, Deadline: r.deadline}, nil
+}
+
+func (s *RegistrationServer) writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		s.Logger.Error(err, "encoding registration response")
+	}
+}
+
+// NewRegistrationServer creates a RegistrationServer authenticating callers via
+// authenticator and registering matchers with mux.
+func NewRegistrationServer(logger logr.Logger, authenticator Authenticator, mux Multiplexer) *RegistrationServer {
+	return &RegistrationServer{Logger: logger, Authenticator: authenticator, Multiplexer: mux}
+}
@@ -11,6 +11,7 @@ import (
 	"net/url"
 	"path"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -60,7 +61,8 @@ type redisHTTPProxy struct {
 	r        *redis.Client
 	h        *http.Client
 	l        *zap.Logger
-	endpoint *url.URL
+	cluster  *Cluster
+	envelope Envelope
 }
 
 // internalHTTPTransport is a roundtripper for the http proxy
@@ -69,10 +71,17 @@ type internalHTTPTransport struct {
 	http.RoundTripper
 	logger *zap.Logger
 	token  string
+	// authHandlers, keyed by lowercased scheme, lets RoundTrip answer a 401's
+	// WWW-Authenticate challenge (e.g. requesting a fresh Bearer token) instead of
+	// only ever attaching the static token as a fallback.
+	authHandlers map[string]AuthHandler
 }
 
 func (t *internalHTTPTransport) RoundTrip(r *http.Request) (*http.Response, error) {
-	r.Header.Set("Authorization", t.token)
+	if len(t.authHandlers) == 0 {
+		r.Header.Set("Authorization", t.token)
+	}
+
 	resp, err := t.RoundTripper.RoundTrip(r)
 	if err != nil {
 		err := &tgAPIError{
@@ -84,12 +93,65 @@ func (t *internalHTTPTransport) RoundTrip(r *http.Request) (*http.Response, erro
 		return nil, err
 	}
 
+	if resp.StatusCode == http.StatusUnauthorized && len(t.authHandlers) > 0 {
+		if retryResp, ok := t.retryWithChallenge(r, resp); ok {
+			return retryResp, nil
+		}
+	}
+
 	if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusUnauthorized {
 		return nil, errors.New("request to bot proxy failed: unauthorized")
 	}
 	return resp, nil
 }
 
+// retryWithChallenge looks for a challenge in resp.Header's WWW-Authenticate
+// values that one of t.authHandlers can satisfy, and, if found, replays the
+// original request (rewinding its body via GetBody if it had one) with the
+// resulting authorization applied. ok is false if no handler could be dispatched,
+// in which case resp should be treated as the final response.
+func (t *internalHTTPTransport) retryWithChallenge(r *http.Request, resp *http.Response) (*http.Response, bool) {
+	for _, challenge := range parseAuthHeader(resp.Header.Values("WWW-Authenticate")) {
+		handler, ok := t.authHandlers[challenge.Scheme]
+		if !ok {
+			continue
+		}
+
+		retryReq := r.Clone(r.Context())
+		if r.GetBody != nil {
+			body, err := r.GetBody()
+			if err != nil {
+				continue
+			}
+			retryReq.Body = body
+		}
+		if err := handler.AuthorizeRequest(retryReq, challenge.Parameters); err != nil {
+			continue
+		}
+
+		resp.Body.Close()
+		retryResp, err := t.RoundTripper.RoundTrip(retryReq)
+		if err != nil {
+			continue
+		}
+		return retryResp, true
+	}
+	return nil, false
+}
+
+// newAuthHandlerMap indexes handlers by their lowercased Scheme(), so RoundTrip can
+// look one up by the challenge's scheme directly.
+func newAuthHandlerMap(handlers []AuthHandler) map[string]AuthHandler {
+	if len(handlers) == 0 {
+		return nil
+	}
+	m := make(map[string]AuthHandler, len(handlers))
+	for _, h := range handlers {
+		m[strings.ToLower(h.Scheme())] = h
+	}
+	return m
+}
+
 // RedisHTTPConfig specifies the configuration of the redis-http-based proxy.
 // All fields are expected to be set unless specified otherwise.
 type RedisHTTPConfig struct {
@@ -97,18 +159,51 @@ type RedisHTTPConfig struct {
 	Redis  *redis.Client
 	// RoundTripper can be nil, in which case http.DefaultTransport will be used
 	RoundTripper http.RoundTripper
-	// Token is the authorization token for the http API
+	// Token is the authorization token for the http API, attached as a static
+	// Authorization header. Ignored if AuthHandlers is non-empty.
 	Token string
-	// Endpoint of the http API
+	// AuthHandlers, if non-empty, makes the http API's responses to a 401 dispatched
+	// to a matching handler (by WWW-Authenticate scheme) instead of relying on Token,
+	// so the proxy can integrate with a token server (e.g. BearerAuthHandler) rather
+	// than requiring a long-lived static token.
+	AuthHandlers []AuthHandler
+	// Endpoint of the http API. Mutually exclusive with Endpoints; equivalent to
+	// Endpoints: []*url.URL{Endpoint}.
 	Endpoint *url.URL
+	// Endpoints lists multiple equivalent http API endpoints, e.g. one per
+	// multiplexer replica behind a rolling deploy. NewRedisHTTPProxy wraps them in a
+	// Cluster, so a registration request fails over to another endpoint instead of
+	// failing outright when the pinned one is mid-restart.
+	Endpoints []*url.URL
+	// Envelope requests a payload format for updates delivered on the registered
+	// channel. EnvelopeRaw (the zero value) is used if unset.
+	Envelope Envelope
 }
 
 // NewRedisHTTPProxy constructs a new redis-http-based proxy
 func NewRedisHTTPProxy(config *RedisHTTPConfig) (Proxy, error) {
 	if config.Logger == nil || config.Redis == nil {
 		return nil, errors.New("unable to create registerer without required components")
-	} else if config.Token == "" || config.Endpoint == nil {
-		return nil, errors.New("token and endpoint of http API must be set")
+	}
+	if config.Token == "" && len(config.AuthHandlers) == 0 {
+		return nil, errors.New("token or auth handlers of http API must be set")
+	}
+
+	endpoints := config.Endpoints
+	if config.Endpoint != nil {
+		endpoints = append(endpoints, config.Endpoint)
+	}
+	if len(endpoints) == 0 {
+		return nil, errors.New("endpoint(s) of http API must be set")
+	}
+
+	urls := make([]string, len(endpoints))
+	for i, e := range endpoints {
+		urls[i] = e.String()
+	}
+	cluster, err := NewCluster(urls)
+	if err != nil {
+		return nil, fmt.Errorf("constructing endpoint cluster: %w", err)
 	}
 
 	transport := config.RoundTripper
@@ -122,56 +217,80 @@ func NewRedisHTTPProxy(config *RedisHTTPConfig) (Proxy, error) {
 				RoundTripper: transport,
 				logger:       config.Logger,
 				token:        config.Token,
+				authHandlers: newAuthHandlerMap(config.AuthHandlers),
 			},
 		},
 		l:        config.Logger,
-		endpoint: config.Endpoint,
+		cluster:  cluster,
+		envelope: config.Envelope,
 	}, nil
 }
 
-func urlJoin(base *url.URL, relative ...string) string {
-	cp := *base
-	cp.RawPath = ""
-	cp.Path = path.Join(append([]string{cp.Path}, relative...)...)
-	return cp.String()
+func urlJoin(base string, relative ...string) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	u.RawPath = ""
+	u.Path = path.Join(append([]string{u.Path}, relative...)...)
+	return u.String(), nil
 }
 
+// updateRegistration posts request to the cluster's registration endpoint,
+// failing over to another endpoint (per Cluster.Do) if one is unreachable or
+// responds with a transport-level error, e.g. because the multiplexer behind it is
+// mid-restart.
 func (p *redisHTTPProxy) updateRegistration(ctx context.Context, request *RegisterHandlerRequest) (*RegisterHandlerResponse, error) {
 	b, err := json.Marshal(request)
 	if err != nil {
 		return nil, fmt.Errorf("marshaling register request failed: %w", err)
 	}
-	buffer := bytes.NewBuffer(b)
 
-	// Do registration request
-	httpreq, err := http.NewRequestWithContext(ctx, "POST", urlJoin(p.endpoint, "internal", "register"), buffer)
-	if err != nil {
-		return nil, fmt.Errorf("http request construction failed: %w", err)
-	}
-	httpreq.Header.Set("Content-Type", "application/json")
+	var resp RegisterHandlerResponse
+	err = p.cluster.Do(ctx, func(ctx context.Context, endpoint string) error {
+		reqURL, err := urlJoin(endpoint, "internal", "register")
+		if err != nil {
+			return fmt.Errorf("invalid endpoint url: %w", err)
+		}
 
-	httpresp, err := p.h.Do(httpreq)
-	if err != nil {
-		return nil, fmt.Errorf("registration request failed: %w", err)
-	}
+		httpreq, err := http.NewRequestWithContext(ctx, "POST", reqURL, bytes.NewReader(b))
+		if err != nil {
+			return fmt.Errorf("http request construction failed: %w", err)
+		}
+		httpreq.Header.Set("Content-Type", "application/json")
 
-	// Ensure that registration was successful
-	body, err := io.ReadAll(httpresp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("reading response failed: %w", err)
-	}
+		httpresp, err := p.h.Do(httpreq)
+		if err != nil {
+			return fmt.Errorf("registration request failed: %w", err)
+		}
+		defer httpresp.Body.Close()
 
-	if httpresp.StatusCode != http.StatusOK && httpresp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("proxy responded with bad status code (%d): %s", httpresp.StatusCode, body)
-	}
+		body, err := io.ReadAll(httpresp.Body)
+		if err != nil {
+			return fmt.Errorf("reading response failed: %w", err)
+		}
 
-	var resp RegisterHandlerResponse
-	if err := json.Unmarshal(body, &resp); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response body %s: %w", body, err)
+		if httpresp.StatusCode != http.StatusOK && httpresp.StatusCode != http.StatusCreated {
+			return fmt.Errorf("proxy responded with bad status code (%d): %s", httpresp.StatusCode, body)
+		}
+
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return fmt.Errorf("failed to unmarshal response body %s: %w", body, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	return &resp, nil
 }
 
+// decodeUpdate decodes a single pubsub message published on a registration's
+// Channel, unwrapping a CloudEvent envelope first if p.envelope requested one.
+func (p *redisHTTPProxy) decodeUpdate(payload string) (tgbotapi.Update, error) {
+	return decodeEnvelopedUpdate([]byte(payload), p.envelope)
+}
+
 func (p *redisHTTPProxy) RegisterHandler(ctx context.Context, name string, matchers []regexp.Regexp) (tgbotapi.UpdatesChannel, error) {
 	var apiError *tgAPIError
 	defer func() {
@@ -184,6 +303,7 @@ func (p *redisHTTPProxy) RegisterHandler(ctx context.Context, name string, match
 	request := RegisterHandlerRequest{
 		Name:     name,
 		Matchers: make([]string, len(matchers)),
+		Envelope: p.envelope,
 	}
 	for i, m := range matchers {
 		request.Matchers[i] = m.String()
@@ -216,8 +336,8 @@ func (p *redisHTTPProxy) RegisterHandler(ctx context.Context, name string, match
 			case <-ctx.Done():
 				return
 			case message := <-subscriber.Channel():
-				var update tgbotapi.Update
-				if err := json.Unmarshal([]byte(message.Payload), &update); err != nil {
+				update, err := p.decodeUpdate(message.Payload)
+				if err != nil {
 					logTgAPIError(p.l, &tgAPIError{
 						wrapped:   err,
 						message:   fmt.Sprintf("failed to unmarshal update message (%s)", message.Payload),
@@ -246,8 +366,19 @@ func (p *redisHTTPProxy) RegisterHandler(ctx context.Context, name string, match
 }
 
 func (p *redisHTTPProxy) InitBotAPI() (*tgbotapi.BotAPI, error) {
-	// construct bot api format with meaningless token
-	bot, err := tgbotapi.NewBotAPIWithClient("fake-token", urlJoin(p.endpoint, "proxy%s", "%s"), p.h)
+	// construct bot api format with meaningless token, against the cluster's
+	// currently pinned endpoint. Unlike updateRegistration, this doesn't fail over
+	// mid-request: the url is baked into the tgbotapi client at construction time,
+	// so a pinned endpoint going down is only noticed (and failed over) on the next
+	// registration renewal.
+	endpointFmt, err := urlJoin(p.cluster.Pinned(), "proxy%s", "%s")
+	if err != nil {
+		err := &tgAPIError{wrapped: err, message: "failed to build bot API endpoint", operation: "init"}
+		logTgAPIError(p.l, err)
+		return nil, err
+	}
+
+	bot, err := tgbotapi.NewBotAPIWithClient("fake-token", endpointFmt, p.h)
 	if err != nil {
 		err := &tgAPIError{
 			wrapped:   err,
@@ -2,6 +2,10 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"sync"
 
 	"github.com/go-logr/logr"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
@@ -36,3 +40,124 @@ func (p *pollingUpdateProvider) Updates(ctx context.Context) tgbotapi.UpdatesCha
 func NewPollingUpdateProvider(logger logr.Logger, api *tgbotapi.BotAPI) UpdateProvider {
 	return &pollingUpdateProvider{logger, api}
 }
+
+// SecretTokenHeader is the header Telegram sets to secret_token on every webhook
+// request once one is configured via proxy.HTTP.SetWebhook, letting
+// WebhookUpdateProvider verify a request actually came from Telegram.
+const SecretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// DefaultUpdatesBuffer sizes a WebhookUpdateProvider's channel if Buffer is unset.
+const DefaultUpdatesBuffer = 100
+
+// WebhookUpdateProvider is an UpdateProvider that receives updates pushed by
+// Telegram to an HTTP endpoint, instead of pollingUpdateProvider's long polling.
+// Register it on a caller-supplied mux/path with its ServeHTTP method, then call
+// Updates to obtain the channel it feeds.
+type WebhookUpdateProvider struct {
+	Logger logr.Logger
+	// SecretToken, if set, must match the X-Telegram-Bot-Api-Secret-Token header on
+	// every request, rejecting mismatches with 401. Pass the same value to
+	// proxy.HTTP.SetWebhook so Telegram actually sends it.
+	SecretToken string
+	// AllowedCIDRs, if set, restricts accepted requests to these ranges (e.g.
+	// Telegram's published webhook IP ranges), rejecting others with 403.
+	AllowedCIDRs []*net.IPNet
+	// Buffer sizes the channel returned by Updates; DefaultUpdatesBuffer is used if zero.
+	Buffer int
+
+	initOnce sync.Once
+	ch       chan tgbotapi.Update
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+func (p *WebhookUpdateProvider) init() {
+	p.initOnce.Do(func() {
+		buf := p.Buffer
+		if buf == 0 {
+			buf = DefaultUpdatesBuffer
+		}
+		p.ch = make(chan tgbotapi.Update, buf)
+	})
+}
+
+// Updates returns the channel ServeHTTP pushes updates to. It is closed once ctx is
+// canceled, after which ServeHTTP stops accepting new updates (responding 503) but
+// any already-buffered ones remain readable until the channel drains.
+func (p *WebhookUpdateProvider) Updates(ctx context.Context) tgbotapi.UpdatesChannel {
+	p.init()
+
+	go func() {
+		<-ctx.Done()
+		p.mu.Lock()
+		p.closed = true
+		close(p.ch)
+		p.mu.Unlock()
+	}()
+	return p.ch
+}
+
+// ServeHTTP decodes the request body as a tgbotapi.Update and pushes it onto the
+// channel returned by Updates, verifying SecretToken and AllowedCIDRs first.
+func (p *WebhookUpdateProvider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.init()
+
+	if p.SecretToken != "" && r.Header.Get(SecretTokenHeader) != p.SecretToken {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if len(p.AllowedCIDRs) > 0 && !p.remoteAllowed(r.RemoteAddr) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var update tgbotapi.Update
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		p.Logger.Error(err, "decoding webhook update")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	select {
+	case p.ch <- update:
+		w.WriteHeader(http.StatusOK)
+	default:
+		p.Logger.Info("dropping webhook update, channel full")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+}
+
+func (p *WebhookUpdateProvider) remoteAllowed(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, cidr := range p.AllowedCIDRs {
+		if cidr.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewWebhookUpdateProvider creates a WebhookUpdateProvider. secretToken may be
+// empty to skip header verification (not recommended outside local development).
+func NewWebhookUpdateProvider(logger logr.Logger, secretToken string) *WebhookUpdateProvider {
+	return &WebhookUpdateProvider{Logger: logger, SecretToken: secretToken}
+}
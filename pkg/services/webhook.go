@@ -0,0 +1,285 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/netutil"
+)
+
+// DefaultTelegramWebhookIPs are the CIDRs Telegram's webhook calls originate from
+// (https://core.telegram.org/bots/webhooks#the-short-version), used as
+// WebhookOptions.AllowedIPs' default.
+var DefaultTelegramWebhookIPs = []*net.IPNet{
+	mustParseCIDR("149.154.160.0/20"),
+	mustParseCIDR("91.108.4.0/22"),
+}
+
+func mustParseCIDR(s string) *net.IPNet {
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// WebhookOptions specifies the configuration used by the webhook streamer.
+type WebhookOptions struct {
+	// Endpoint is the Telegram Bot API root, e.g. "https://api.telegram.org", used
+	// for the setWebhook/deleteWebhook bootstrap calls.
+	Endpoint string
+	// Token is the bot token authorizing those calls.
+	Token string
+	// BaseURL is the externally-reachable base URL Telegram is told to POST
+	// updates to; Path is joined onto it to build the advertised webhook URL.
+	BaseURL string
+	// Path is where the http.Server listens for Telegram's POSTed updates,
+	// both locally and as joined onto BaseURL. Defaults to "/".
+	Path string
+	// Secret, if set, is checked against the X-Telegram-Bot-Api-Secret-Token
+	// header of every incoming request and sent as setWebhook's secret_token.
+	Secret string
+	// CertFile and KeyFile are optional; when unset the server is started using
+	// plain HTTP, which is only sensible behind a TLS-terminating reverse proxy.
+	CertFile string
+	KeyFile  string
+	// Listener is accepted on by the webhook's http.Server. Required.
+	Listener net.Listener
+	// AllowedIPs restricts which peers may reach the webhook handler. Since
+	// Telegram itself is the only legitimate caller, DefaultTelegramWebhookIPs is
+	// used if nil; pass an explicit non-nil slice (e.g. including ::1/128) to lift
+	// the restriction for local testing.
+	AllowedIPs []*net.IPNet
+	// MaxConnections caps how many simultaneous HTTPS connections Telegram keeps
+	// open to the webhook, forwarded to setWebhook's max_connections. Telegram's
+	// own default of 40 is used if zero.
+	MaxConnections int
+	// AllowedUpdates restricts which update types are delivered, forwarded to
+	// setWebhook's allowed_updates. All update types are delivered if empty.
+	AllowedUpdates []string
+	// TrustedProxies lists the CIDRs of reverse proxies allowed to set X-Real-IP/X-Forwarded-For.
+	// If the immediate peer isn't in this list, those headers are ignored and RemoteAddr is used.
+	TrustedProxies []*net.IPNet
+	// Client is used for the setWebhook/deleteWebhook bootstrap calls. http.DefaultClient is used if nil.
+	Client *http.Client
+}
+
+type webhookStreamer struct {
+	opts        WebhookOptions
+	endpointURL *url.URL
+	webhookURL  string
+}
+
+// realIP returns the best-effort client IP for r, honoring X-Real-IP/X-Forwarded-For
+// only when the immediate peer address is amongst opts.TrustedProxies.
+func realIP(r *http.Request, trusted []*net.IPNet) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	peer := net.ParseIP(host)
+	trustedPeer := false
+	for _, cidr := range trusted {
+		if peer != nil && cidr.Contains(peer) {
+			trustedPeer = true
+			break
+		}
+	}
+	if !trustedPeer {
+		return host
+	}
+
+	if ip := r.Header.Get("X-Real-IP"); ip != "" {
+		return ip
+	}
+	if ips := r.Header.Get("X-Forwarded-For"); ips != "" {
+		return ips
+	}
+	return host
+}
+
+// ipAllowed reports whether ip (as returned by realIP) falls within allowed. A
+// malformed ip (e.g. a comma-joined X-Forwarded-For list) is never allowed.
+func ipAllowed(ip string, allowed []*net.IPNet) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, cidr := range allowed {
+		if cidr.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *webhookStreamer) callEndpoint(ctx context.Context, method string, params url.Values) error {
+	u := *s.endpointURL
+	u.Path = path.Join(u.Path, method)
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("preparing %s request: %w", method, err)
+	}
+
+	client := s.opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("calling %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading %s response: %w", method, err)
+	}
+
+	var apiResp struct {
+		Ok          bool
+		Description string
+	}
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return fmt.Errorf("parsing %s response: %w", method, err)
+	}
+	if !apiResp.Ok {
+		return fmt.Errorf("%s failed: %s", method, apiResp.Description)
+	}
+	return nil
+}
+
+func (s *webhookStreamer) setWebhook(ctx context.Context) error {
+	params := url.Values{}
+	params.Set("url", s.webhookURL)
+	if s.opts.Secret != "" {
+		params.Set("secret_token", s.opts.Secret)
+	}
+	if s.opts.MaxConnections != 0 {
+		params.Set("max_connections", strconv.Itoa(s.opts.MaxConnections))
+	}
+	if len(s.opts.AllowedUpdates) != 0 {
+		allowed, err := json.Marshal(s.opts.AllowedUpdates)
+		if err != nil {
+			return fmt.Errorf("marshaling allowed_updates: %w", err)
+		}
+		params.Set("allowed_updates", string(allowed))
+	}
+	return s.callEndpoint(ctx, "setWebhook", params)
+}
+
+func (s *webhookStreamer) deleteWebhook(ctx context.Context) error {
+	return s.callEndpoint(ctx, "deleteWebhook", url.Values{})
+}
+
+func (s *webhookStreamer) handler(stream chan<- Maybe[RawUpdate]) http.Handler {
+	allowedIPs := s.opts.AllowedIPs
+	if allowedIPs == nil {
+		allowedIPs = DefaultTelegramWebhookIPs
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		peer := realIP(r, s.opts.TrustedProxies)
+		if !ipAllowed(peer, allowedIPs) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		if s.opts.Secret != "" && r.Header.Get("X-Telegram-Bot-Api-Secret-Token") != s.opts.Secret {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, int64(DefaultDecodeBufferSize)))
+		if err != nil {
+			stream <- Maybe[RawUpdate]{Error: fmt.Errorf("reading webhook body (from %s): %w", peer, err)}
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		stream <- Maybe[RawUpdate]{Value: RawUpdate(body)}
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func (s *webhookStreamer) Stream(ctx context.Context) RawStream {
+	stream := make(chan Maybe[RawUpdate], DefaultCapacity)
+
+	mux := http.NewServeMux()
+	mux.Handle(s.opts.Path, s.handler(stream))
+	server := &http.Server{Handler: mux}
+
+	listener := s.opts.Listener
+	if s.opts.MaxConnections > 0 {
+		listener = netutil.LimitListener(listener, s.opts.MaxConnections)
+	}
+
+	go func() {
+		defer close(stream)
+
+		if err := s.setWebhook(ctx); err != nil {
+			stream <- Maybe[RawUpdate]{Error: fmt.Errorf("bootstrapping webhook: %w", err)}
+			return
+		}
+
+		errCh := make(chan error, 1)
+		go func() {
+			var err error
+			if s.opts.CertFile != "" || s.opts.KeyFile != "" {
+				err = server.ServeTLS(listener, s.opts.CertFile, s.opts.KeyFile)
+			} else {
+				err = server.Serve(listener)
+			}
+			if !errors.Is(err, http.ErrServerClosed) {
+				errCh <- err
+			}
+		}()
+
+		select {
+		case <-ctx.Done():
+			if err := s.deleteWebhook(context.Background()); err != nil {
+				stream <- Maybe[RawUpdate]{Error: fmt.Errorf("tearing down webhook: %w", err)}
+			}
+			_ = server.Shutdown(context.Background())
+		case err := <-errCh:
+			stream <- Maybe[RawUpdate]{Error: fmt.Errorf("webhook server failed: %w", err)}
+		}
+	}()
+	return stream
+}
+
+// NewWebhookStreamer starts a webhook-based streamer which receives updates POSTed by
+// Telegram to opts.BaseURL+opts.Path, served locally on opts.Listener. It participates
+// in the same Stream(ctx).AsTgBotAPI() contract as the long poll streamer, so the two
+// are interchangeable from the caller's perspective.
+func NewWebhookStreamer(opts WebhookOptions) (RawStreamer, error) {
+	if opts.Listener == nil {
+		return nil, errors.New("webhook streamer requires a listener")
+	}
+
+	endpointURL, err := url.Parse(opts.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook endpoint: %w", err)
+	}
+	endpointURL.Path = path.Join(endpointURL.Path, "bot"+opts.Token)
+
+	if opts.Path == "" {
+		opts.Path = "/"
+	}
+	webhookURL := strings.TrimRight(opts.BaseURL, "/") + "/" + strings.TrimLeft(opts.Path, "/")
+
+	return &webhookStreamer{opts: opts, endpointURL: endpointURL, webhookURL: webhookURL}, nil
+}
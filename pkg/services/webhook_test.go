@@ -0,0 +1,73 @@
+package services
+
+import (
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIPAllowed(t *testing.T) {
+	tests := []struct {
+		name string
+		ip   string
+		want bool
+	}{
+		{name: "within telegram's first default range", ip: "149.154.167.1", want: true},
+		{name: "within telegram's second default range", ip: "91.108.4.1", want: true},
+		{name: "outside both default ranges", ip: "8.8.8.8", want: false},
+		{name: "malformed ip", ip: "not-an-ip", want: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			req := require.New(t)
+			req.Equal(tt.want, ipAllowed(tt.ip, DefaultTelegramWebhookIPs))
+		})
+	}
+}
+
+func TestRealIP(t *testing.T) {
+	_, trusted, err := net.ParseCIDR("10.0.0.0/8")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		want       string
+	}{
+		{
+			name:       "untrusted peer, forwarded header ignored",
+			remoteAddr: "1.2.3.4:1234",
+			headers:    map[string]string{"X-Real-IP": "5.6.7.8"},
+			want:       "1.2.3.4",
+		},
+		{
+			name:       "trusted peer, X-Real-IP honored",
+			remoteAddr: "10.0.0.1:1234",
+			headers:    map[string]string{"X-Real-IP": "5.6.7.8"},
+			want:       "5.6.7.8",
+		},
+		{
+			name:       "trusted peer, no forwarded headers",
+			remoteAddr: "10.0.0.1:1234",
+			want:       "10.0.0.1",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			req := require.New(t)
+
+			r := &http.Request{RemoteAddr: tt.remoteAddr, Header: http.Header{}}
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+			req.Equal(tt.want, realIP(r, []*net.IPNet{trusted}))
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookUpdateProvider_PushesDecodedUpdate(t *testing.T) {
+	req := require.New(t)
+
+	p := NewWebhookUpdateProvider(logr.Discard(), "")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates := p.Updates(ctx)
+
+	rec := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(`{"update_id":1}`))
+	p.ServeHTTP(rec, r)
+	req.Equal(http.StatusOK, rec.Code)
+
+	select {
+	case update := <-updates:
+		req.Equal(1, update.UpdateID)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for update")
+	}
+}
+
+func TestWebhookUpdateProvider_RejectsWrongSecret(t *testing.T) {
+	req := require.New(t)
+
+	p := NewWebhookUpdateProvider(logr.Discard(), "expected")
+	p.Updates(context.Background())
+
+	rec := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(`{"update_id":1}`))
+	r.Header.Set(SecretTokenHeader, "wrong")
+	p.ServeHTTP(rec, r)
+	req.Equal(http.StatusUnauthorized, rec.Code)
+}
+
+func TestWebhookUpdateProvider_AcceptsCorrectSecret(t *testing.T) {
+	req := require.New(t)
+
+	p := NewWebhookUpdateProvider(logr.Discard(), "expected")
+	p.Updates(context.Background())
+
+	rec := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(`{"update_id":1}`))
+	r.Header.Set(SecretTokenHeader, "expected")
+	p.ServeHTTP(rec, r)
+	req.Equal(http.StatusOK, rec.Code)
+}
+
+func TestWebhookUpdateProvider_RejectsDisallowedCIDR(t *testing.T) {
+	req := require.New(t)
+
+	_, allowed, err := net.ParseCIDR("10.0.0.0/8")
+	req.NoError(err)
+
+	p := NewWebhookUpdateProvider(logr.Discard(), "")
+	p.AllowedCIDRs = []*net.IPNet{allowed}
+	p.Updates(context.Background())
+
+	rec := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(`{"update_id":1}`))
+	r.RemoteAddr = "203.0.113.5:1234"
+	p.ServeHTTP(rec, r)
+	req.Equal(http.StatusForbidden, rec.Code)
+}
+
+func TestWebhookUpdateProvider_DrainsAfterCancel(t *testing.T) {
+	req := require.New(t)
+
+	p := NewWebhookUpdateProvider(logr.Discard(), "")
+	ctx, cancel := context.WithCancel(context.Background())
+	updates := p.Updates(ctx)
+
+	rec := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(`{"update_id":1}`))
+	p.ServeHTTP(rec, r)
+	req.Equal(http.StatusOK, rec.Code)
+
+	cancel()
+
+	// Buffered update is still delivered even after the provider was told to stop.
+	req.Eventually(func() bool {
+		select {
+		case update, ok := <-updates:
+			req.True(ok)
+			req.Equal(1, update.UpdateID)
+			return true
+		default:
+			return false
+		}
+	}, time.Second, time.Millisecond*10)
+
+	// New requests are rejected once draining has started.
+	req.Eventually(func() bool {
+		rec := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(`{"update_id":2}`))
+		p.ServeHTTP(rec, r)
+		return rec.Code == http.StatusServiceUnavailable
+	}, time.Second, time.Millisecond*10)
+}